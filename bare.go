@@ -0,0 +1,83 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import "github.com/spf13/pflag"
+
+// impliedHelp is appended to the completion help text of the bare enum value.
+const impliedHelp = "(implied)"
+
+// WithBareValue sets the enum value implied when the flag is given without
+// any value, such as “--color” meaning “--color=always”, while explicit
+// values, such as “--color=never”, still work. The canonical name of the bare
+// enum value becomes the [github.com/spf13/pflag.Flag.NoOptDefVal] when
+// registering the flag using [EnumFlagValue.VarP], and is returned by
+// [EnumFlagValue.NoOptDefVal]. pflag shows it in help, such as
+// “--color mode[=always]”, and completion marks it as implied.
+//
+// The enum flag value constructors panic if the bare enum value isn't mapped,
+// or if it isn't of the enum type of the flag value. [NewLevel] panics on
+// bare enum values, as bare level flags step through the levels instead.
+func WithBareValue[E comparable](enumval E) Option {
+	return func(o *options) {
+		o.bare = enumval
+	}
+}
+
+// quoter is implemented by enum values whose identifiers might need quoting,
+// such as slice enum values using [WithQuoting].
+type quoter interface {
+	quote(id string) string
+}
+
+// NoOptDefVal returns the canonical name of the bare enum value set using
+// [WithBareValue], for use as the [github.com/spf13/pflag.Flag.NoOptDefVal].
+// For slice and set enum flags, the canonical name gets quoted as necessary
+// (see [WithQuoting]). If there is no bare enum value, NoOptDefVal returns an
+// empty string.
+func (e *EnumFlagValue[E]) NoOptDefVal() string {
+	enumval, ok := e.names.Bare()
+	if !ok {
+		return ""
+	}
+	name := e.names.Lookup(enumval)[0]
+	if q, ok := e.value.(quoter); ok {
+		return q.quote(name)
+	}
+	return name
+}
+
+// VarP defines the flag with the specified name, shorthand, and usage in the
+// flag set, with its NoOptDefVal set to the canonical name of the bare enum
+// value, if any; see [WithBareValue]. VarP returns the newly defined flag.
+func (e *EnumFlagValue[E]) VarP(fs *pflag.FlagSet, name, shorthand, usage string) *pflag.Flag {
+	return varP(fs, e, name, shorthand, usage, e.NoOptDefVal())
+}
+
+// VarP defines the slice flag with the specified name, shorthand, and usage
+// in the flag set, the same as [EnumFlagValue.VarP] does, but registering the
+// slice enum flag value itself, so that the flag's Value remains a
+// [github.com/spf13/pflag.SliceValue]. VarP returns the newly defined flag.
+func (e *EnumSliceFlagValue[E]) VarP(fs *pflag.FlagSet, name, shorthand, usage string) *pflag.Flag {
+	return varP(fs, e, name, shorthand, usage, e.NoOptDefVal())
+}
+
+// varP defines the flag with the specified Value, name, shorthand, usage, and
+// NoOptDefVal in the flag set, returning the newly defined flag.
+func varP(fs *pflag.FlagSet, value pflag.Value, name, shorthand, usage, nooptdefval string) *pflag.Flag {
+	flag := fs.VarPF(value, name, shorthand, usage)
+	flag.NoOptDefVal = nooptdefval
+	return flag
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bare enum flag values", func() {
+
+	newFlagSet := func(foomode *FooModeTest, opts ...Option) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		New(foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive,
+			append([]Option{WithOrder(fmFoo, fmBar, fmBaz)}, opts...)...).
+			VarP(fs, "mode", "m", "foos the output")
+		return fs
+	}
+
+	DescribeTable("implies the bare enum value",
+		func(args []string, expected FooModeTest) {
+			foomode := fmFoo
+			fs := newFlagSet(&foomode, WithBareValue(fmBar))
+			Expect(fs.Parse(args)).To(Succeed())
+			Expect(foomode).To(Equal(expected))
+		},
+		Entry(nil, []string{}, fmFoo),
+		Entry(nil, []string{"--mode"}, fmBar),
+		Entry(nil, []string{"-m"}, fmBar),
+		Entry(nil, []string{"--mode=baz"}, fmBaz),
+		Entry(nil, []string{"--mode=BAZ", "--mode"}, fmBar),
+	)
+
+	It("shows the canonical name of the bare enum value in help", func() {
+		foomode := fmFoo
+		fs := newFlagSet(&foomode, WithBareValue(fmBar))
+		Expect(fs.Lookup("mode").NoOptDefVal).To(Equal("bar"))
+		Expect(fs.FlagUsages()).To(MatchRegexp(`-m, --mode mode\[=bar\]\s+foos the output \(default foo\)`))
+	})
+
+	It("requires values without a bare enum value", func() {
+		foomode := fmFoo
+		fs := newFlagSet(&foomode)
+		Expect(fs.Lookup("mode").NoOptDefVal).To(BeEmpty())
+		Expect(fs.Parse([]string{"--mode"})).To(MatchError(ContainSubstring("needs an argument")))
+	})
+
+	It("marks the bare enum value as implied in completions", func() {
+		foomode := fmFoo
+		flag := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(fmFoo, fmBar, fmBaz), WithBareValue(fmBar))
+		completor := flag.value.NewCompletor(flag.names, Help[FooModeTest]{fmBar: "barz", fmBaz: "bazz"})
+		completions, _ := completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"foo", "bar\tbarz (implied)", "Bar\tbarz (implied)", "baz\tbazz"}))
+
+		flag = New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(fmFoo, fmBar, fmBaz), WithBareValue(fmBaz))
+		completor = flag.value.NewCompletor(flag.names, nil)
+		completions, _ = completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"foo", "bar", "Bar", "baz\t(implied)"}))
+	})
+
+	It("implies bare slice elements", func() {
+		var foomodes []FooModeTest
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithBareValue(fmBaz)).
+			VarP(fs, "modes", "m", "foos the output")
+		Expect(fs.Parse([]string{"-m", "--modes=foo"})).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		Expect(fs.Lookup("modes").Value).To(Satisfy(func(v pflag.Value) bool {
+			_, ok := v.(pflag.SliceValue)
+			return ok
+		}))
+		Expect(fs.Lookup("modes").Value).To(BeAssignableToTypeOf(&EnumSliceFlagValue[FooModeTest]{}))
+	})
+
+	It("quotes bare slice and set elements", func() {
+		mapping := EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo,bar"},
+			fmBaz: {"baz"},
+		}
+		var foomodes []FooModeTest
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		slice := NewSlice(&foomodes, "modes", mapping, EnumCaseInsensitive,
+			WithQuoting(), WithBareValue(fmFoo))
+		Expect(slice.NoOptDefVal()).To(Equal(`"foo,bar"`))
+		slice.VarP(fs, "modes", "m", "foos the output")
+
+		foomodeset := NewEnumSet[FooModeTest]()
+		set := NewSet(&foomodeset, "modes", mapping, EnumCaseInsensitive,
+			WithQuoting(), WithBareValue(fmFoo))
+		Expect(set.NoOptDefVal()).To(Equal(`"foo,bar"`))
+		set.VarP(fs, "modeset", "s", "foos the output")
+
+		Expect(fs.Parse([]string{"-m", "-s"})).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmFoo}))
+		Expect(foomodeset.Values()).To(Equal([]FooModeTest{fmFoo}))
+	})
+
+	It("panics on invalid bare enum values", func() {
+		foomode := fmFoo
+		Expect(func() {
+			New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive, WithBareValue(FooModeTest(42)))
+		}).To(PanicWith(MatchRegexp(`bare enum value 42 isn't mapped`)))
+		Expect(func() {
+			New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive, WithBareValue(42))
+		}).To(PanicWith(MatchRegexp(`bare enum value 42 is of type int instead of enumflag.FooModeTest`)))
+	})
+
+	It("panics on bare level values", func() {
+		verbosity := vbInfo
+		Expect(func() {
+			NewLevel(&verbosity, "level", verbosityIdentifiersTest, EnumCaseInsensitive,
+				WithOrder(vbWarn, vbInfo), WithBareValue(vbInfo))
+		}).To(PanicWith(MatchRegexp(`NewLevel doesn't support bare enum values`)))
+	})
+
+})
@@ -0,0 +1,183 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type permTest uint8
+
+const (
+	permRead permTest = 1 << iota
+	permWrite
+	permExec
+	permAll = permRead | permWrite | permExec
+)
+
+var permIdentifiersTest = EnumIdentifiers[permTest]{
+	permRead:  {"read", "r"},
+	permWrite: {"write", "w"},
+	permExec:  {"exec", "x"},
+	permAll:   {"all"},
+}
+
+var _ = Describe("bitmasks", func() {
+
+	DescribeTable("splits at any separator",
+		func(s string, expected []string) {
+			Expect(splitAny(s, bitmaskSeparators)).To(Equal(expected))
+		},
+		Entry(nil, "", []string{""}),
+		Entry(nil, "read", []string{"read"}),
+		Entry(nil, "read,write|exec", []string{"read", "write", "exec"}),
+		Entry(nil, "read,,", []string{"read", "", ""}),
+	)
+
+	DescribeTable("sets bitmasks",
+		func(input string, expected permTest) {
+			perm := permRead
+			flag := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+			Expect(flag.Set(input)).To(Succeed())
+			Expect(perm).To(Equal(expected))
+			Expect(flag.GetValue()).To(Equal(expected))
+		},
+		Entry(nil, "write", permWrite),
+		Entry(nil, "write,exec", permWrite|permExec),
+		Entry(nil, "write|X", permWrite|permExec),
+		Entry(nil, "r|w,x", permAll),
+		Entry(nil, "all,read", permAll),
+	)
+
+	It("merges subsequent sets", func() {
+		perm := permRead
+		flag := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("write")).To(Succeed())
+		Expect(flag.Set("exec")).To(Succeed())
+		Expect(perm).To(Equal(permWrite | permExec))
+	})
+
+	It("rejects invalid elements", func() {
+		perm := permRead
+		flag := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+		err := flag.Set("write|rwx")
+		Expect(err).To(HaveOccurred())
+		ierr := err.(*InvalidValueError)
+		Expect(ierr.Type).To(Equal("perm"))
+		Expect(ierr.Input).To(Equal("rwx"))
+		Expect(ierr.Index).To(Equal(1))
+		Expect(ierr.Offset).To(Equal(6))
+		Expect(flag.Set("read,")).NotTo(Succeed())
+		Expect(flag.Set("0x10")).NotTo(Succeed())
+		Expect(flag.Set("0xg")).NotTo(Succeed())
+		Expect(perm).To(Equal(permRead))
+	})
+
+	DescribeTable("stringifies",
+		func(perm permTest, expected string) {
+			b := enumBitmask[permTest]{v: &perm}
+			Expect(b.String(newEnumMapper(permIdentifiersTest, EnumCaseInsensitive))).To(Equal(expected))
+		},
+		Entry(nil, permTest(0), ""),
+		Entry(nil, permWrite, "write"),
+		Entry(nil, permRead|permExec, "read|exec"),
+		Entry(nil, permAll, "all"),
+		Entry(nil, permTest(0x10), "0x10"),
+		Entry(nil, permAll|0x30, "all|0x30"),
+		Entry(nil, permWrite|0x80, "write|0x80"),
+	)
+
+	It("clears on empty input", func() {
+		perm := permRead
+		flag := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("")).To(Succeed())
+		Expect(perm).To(BeZero())
+		Expect(flag.Set("write")).To(Succeed())
+		Expect(flag.Set("")).To(Succeed())
+		Expect(perm).To(BeZero())
+	})
+
+	DescribeTable("round-trips its textual representation",
+		func(mapping EnumIdentifiers[permTest], perm permTest) {
+			flag := NewBitmask(&perm, "perm", mapping, EnumCaseInsensitive)
+			expected := perm
+			Expect(flag.Set(flag.String())).To(Succeed())
+			Expect(perm).To(Equal(expected))
+		},
+		Entry("zero", permIdentifiersTest, permTest(0)),
+		Entry("composite", permIdentifiersTest, permAll),
+		Entry("decomposed", permIdentifiersTest, permRead|permExec),
+		Entry("leftover bits", EnumIdentifiers[permTest]{
+			permRead: {"read"},
+			permAll:  {"all"},
+		}, permRead|permWrite),
+	)
+
+	It("renders leftover bits", func() {
+		perm := permRead | permWrite
+		flag := NewBitmask(&perm, "perm", EnumIdentifiers[permTest]{
+			permRead: {"read"},
+			permAll:  {"all"},
+		}, EnumCaseInsensitive)
+		Expect(flag.String()).To(Equal("read|0x2"))
+	})
+
+	It("decomposes composites first", func() {
+		mapping := EnumIdentifiers[permTest]{
+			permRead:             {"read"},
+			permWrite:            {"write"},
+			permExec:             {"exec"},
+			permRead | permWrite: {"rw"},
+		}
+		perm := permRead | permWrite | permExec
+		flag := NewBitmask(&perm, "perm", mapping, EnumCaseInsensitive)
+		Expect(flag.String()).To(Equal("rw|exec"))
+	})
+
+	It("completes like slices", func() {
+		perm := permRead
+		flag := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(permRead, permWrite, permExec, permAll))
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, directive := completor(&cobra.Command{}, nil, "read|W,")
+		Expect(completions).To(Equal([]string{
+			"read|W,r", "read|W,write", "read|W,exec", "read|W,x", "read|W,all"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp |
+			cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveKeepOrder))
+	})
+
+	It("panics on invalid mappings and values", func() {
+		perm := permRead
+		Expect(func() {
+			_ = NewBitmask[permTest](nil, "perm", permIdentifiersTest, EnumCaseInsensitive)
+		}).To(PanicWith(MatchRegexp(`NewBitmask requires flag to be a non-nil pointer`)))
+		Expect(func() {
+			_ = NewBitmask(&perm, "perm", nil, EnumCaseInsensitive)
+		}).To(PanicWith(MatchRegexp(`NewBitmask requires mapping not to be nil`)))
+		Expect(func() {
+			_ = NewBitmask(&perm, "perm", EnumIdentifiers[permTest]{
+				permRead: {"read|only"},
+			}, EnumCaseInsensitive)
+		}).To(PanicWith(MatchRegexp(`identifier 'read\|only' of enum value 1 contains separator '\|'`)))
+		perm = 0x10 | permRead
+		Expect(func() {
+			_ = NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+		}).To(PanicWith(MatchRegexp(`NewBitmask requires flag to reference mapped bits only, but 0x10 isn't mapped`)))
+	})
+
+})
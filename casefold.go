@@ -0,0 +1,105 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// fullFolder implements full Unicode case folding; it is stateless and thus
+// safe for concurrent use.
+var fullFolder = cases.Fold()
+
+// fold returns the specified textual representation adjusted to the case
+// sensitivity, so that adjusted representations can be directly compared.
+func (s EnumCaseSensitivity) fold(id string) string {
+	if s == EnumCaseSensitive {
+		return id
+	}
+	return strings.ToLower(id)
+}
+
+// fold returns the specified textual representation case folded, so that
+// folded representations can be directly compared.
+func (f EnumCaseFolding) fold(id string) string {
+	if f != EnumCaseFoldFull {
+		return simpleFold(id)
+	}
+	if isASCII(id) {
+		// Full case folding of ASCII boils down to lowercasing, but without
+		// the overhead of the full folding machinery.
+		return strings.ToLower(id)
+	}
+	return fullFolder.String(id)
+}
+
+// simpleFold returns the specified string with all its runes replaced by the
+// representatives of their simple Unicode case folding orbits. Two strings
+// are equal under simple Unicode case folding, as in [strings.EqualFold], if
+// and only if their simpleFold'ed forms are equal. As an optimization, the
+// string is returned as-is when it already is in its folded form, avoiding
+// any allocation.
+func simpleFold(s string) string {
+	for idx, r := range s {
+		if foldRune(r) == r {
+			continue
+		}
+		var b strings.Builder
+		b.Grow(len(s))
+		b.WriteString(s[:idx])
+		for _, r := range s[idx:] {
+			b.WriteRune(foldRune(r))
+		}
+		return b.String()
+	}
+	return s
+}
+
+// foldRune returns the representative of the simple Unicode case folding
+// orbit of the specified rune. The representative is the smallest lower case
+// rune in the orbit, if any, otherwise the smallest rune in the orbit.
+// Preferring lower case runes makes folding most lower case identifiers a
+// no-op.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}
+	rep, replower := r, unicode.IsLower(r)
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		lower := unicode.IsLower(f)
+		if (lower && !replower) || (lower == replower && f < rep) {
+			rep, replower = f, lower
+		}
+	}
+	return rep
+}
+
+// isASCII returns true if the specified string consists of ASCII characters
+// only.
+func isASCII(s string) bool {
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("case folding", func() {
+
+	DescribeTable("folds",
+		func(sensitivity Matcher, id string, expected string) {
+			Expect(sensitivity.Normalize(id)).To(Equal(expected))
+		},
+		Entry(nil, EnumCaseSensitive, "FooBar", "FooBar"),
+		Entry(nil, EnumCaseInsensitive, "FooBar", "foobar"),
+		Entry(nil, EnumCaseFold, "FooBar", "foobar"),
+		Entry(nil, EnumCaseFold, "foobar", "foobar"),
+		Entry(nil, EnumCaseFold, "Kelvin", "kelvin"), // Kelvin sign
+		Entry(nil, EnumCaseFold, "MØØ", "møø"),
+		Entry(nil, EnumCaseFold, "straße", "straße"),
+		Entry(nil, EnumCaseFoldFull, "STRASSE", "strasse"),
+		Entry(nil, EnumCaseFoldFull, "Straße", "strasse"),
+	)
+
+	words := []string{
+		"foo", "FOO", "Foo", "kelvin", "Kelvin", "KELVIN",
+		"σίσυφος", "ΣΊΣΥΦΟΣ", "σίσυφοσ", "ς", "Σ", "σ",
+		"straße", "STRASSE", "strasse", "STRAẞE",
+		"ǆ", "ǅ", "Ǆ", "ſ", "s", "S", "i", "I", "İ", "ı",
+		"møø", "MØØ", "mimimi",
+	}
+
+	It("simple-folds equivalent to strings.EqualFold", func() {
+		for _, a := range words {
+			for _, b := range words {
+				Expect(simpleFold(a) == simpleFold(b)).To(Equal(strings.EqualFold(a, b)),
+					"%q vs. %q", a, b)
+			}
+		}
+	})
+
+	It("doesn't allocate when already folded", func() {
+		Expect(testing.AllocsPerRun(100, func() { _ = simpleFold("value-42") })).To(BeZero())
+	})
+
+	DescribeTable("looks up value for name",
+		func(name string, sensitivity Matcher, expected FooModeTest) {
+			mapper := newEnumMapper(EnumIdentifiers[FooModeTest]{
+				fmFoo: {"straße"},
+				fmBar: {"møø"},
+			}, sensitivity)
+			Expect(mapper.ValueOf(name)).To(Equal(expected))
+		},
+		Entry(nil, "STRAẞE", EnumCaseFold, fmFoo),
+		Entry(nil, "STRASSE", EnumCaseFoldFull, fmFoo),
+		Entry(nil, "strasse", EnumCaseFoldFull, fmFoo),
+		Entry(nil, "MØØ", EnumCaseFold, fmBar),
+		Entry(nil, "MØØ", EnumCaseFoldFull, fmBar),
+	)
+
+	It("keeps case sensitivities convertible from bools", func() {
+		caseSensitive := true
+		Expect(EnumCaseSensitivity(caseSensitive)).To(Equal(EnumCaseSensitive))
+		Expect(EnumCaseSensitivity(!caseSensitive)).To(Equal(EnumCaseInsensitive))
+	})
+
+	It("doesn't fully fold in simple fold mode", func() {
+		mapper := newEnumMapper(EnumIdentifiers[FooModeTest]{
+			fmFoo: {"straße"},
+		}, EnumCaseFold)
+		Expect(mapper.ValueOf("STRASSE")).Error().To(HaveOccurred())
+	})
+
+})
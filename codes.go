@@ -0,0 +1,55 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// codeOf returns the numeric code of the specified enum value in canonical
+// decimal form, and true if the enum type is integer-kinded. Otherwise, it
+// returns false.
+func codeOf[E comparable](enumval E) (string, bool) {
+	v := reflect.ValueOf(enumval)
+	switch {
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10), true
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+	return "", false
+}
+
+// isIntegerEnum returns true if the enum type E is integer-kinded.
+func isIntegerEnum[E comparable]() bool {
+	var zero E
+	_, ok := codeOf(zero)
+	return ok
+}
+
+// parseCode parses the specified user input as a numeric code in decimal,
+// hexadecimal (“0x”), octal (“0o”), or binary (“0b”) notation, with an
+// optional sign, returning the code in canonical decimal form. If the user
+// input isn't a number, parseCode returns false.
+func parseCode(s string) (string, bool) {
+	if code, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return strconv.FormatInt(code, 10), true
+	}
+	if code, err := strconv.ParseUint(s, 0, 64); err == nil {
+		return strconv.FormatUint(code, 10), true
+	}
+	return "", false
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type levelTest int8
+
+const (
+	lvlDebug levelTest = -4
+	lvlInfo  levelTest = 0
+	lvlWarn  levelTest = 4
+	lvlError levelTest = 8
+	lvlFatal levelTest = 0x1f
+)
+
+var levelIdentifiersTest = EnumIdentifiers[levelTest]{
+	lvlDebug: {"debug"},
+	lvlInfo:  {"info"},
+	lvlWarn:  {"warn", "8"}, // sic!
+	lvlError: {"error"},
+	lvlFatal: {"fatal"},
+}
+
+var _ = Describe("numeric codes", func() {
+
+	DescribeTable("parses codes",
+		func(input string, expected string) {
+			code, ok := parseCode(input)
+			Expect(ok).To(BeTrue())
+			Expect(code).To(Equal(expected))
+		},
+		Entry(nil, "42", "42"),
+		Entry(nil, "-42", "-42"),
+		Entry(nil, "0x2a", "42"),
+		Entry(nil, "-0x2A", "-42"),
+		Entry(nil, "18446744073709551615", "18446744073709551615"),
+	)
+
+	It("rejects non-numeric codes", func() {
+		for _, input := range []string{"", "foo", "0xg", "4 2", "--1"} {
+			_, ok := parseCode(input)
+			Expect(ok).To(BeFalse(), "input %q", input)
+		}
+	})
+
+	It("knows integer enum types", func() {
+		Expect(isIntegerEnum[levelTest]()).To(BeTrue())
+		Expect(isIntegerEnum[Flag]()).To(BeTrue())
+		Expect(isIntegerEnum[string]()).To(BeFalse())
+		Expect(isIntegerEnum[float64]()).To(BeFalse())
+	})
+
+	DescribeTable("sets scalar flag from codes",
+		func(input string, expected levelTest) {
+			level := lvlInfo
+			flag := New(&level, "level", levelIdentifiersTest, EnumCaseInsensitive, WithNumericCodes())
+			Expect(flag.Set(input)).To(Succeed())
+			Expect(level).To(Equal(expected))
+		},
+		Entry(nil, "4", lvlWarn),
+		Entry(nil, "-4", lvlDebug),
+		Entry(nil, "0", lvlInfo),
+		Entry(nil, "0x1f", lvlFatal),
+		Entry(nil, "0X1F", lvlFatal),
+		Entry(nil, "8", lvlWarn), // identifiers take precedence
+		Entry(nil, "error", lvlError),
+	)
+
+	It("rejects unmapped codes", func() {
+		level := lvlInfo
+		flag := New(&level, "level", levelIdentifiersTest, EnumCaseInsensitive, WithNumericCodes())
+		for _, input := range []string{"1", "-1", "0x10", "128"} {
+			Expect(flag.Set(input)).To(MatchError(
+				"must be 'debug', 'error', 'fatal', 'info', 'warn'/'8'"), "input %q", input)
+		}
+		Expect(level).To(Equal(lvlInfo))
+	})
+
+	It("rejects codes unless enabled", func() {
+		level := lvlInfo
+		flag := New(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("4")).NotTo(Succeed())
+	})
+
+	It("stringifies codes as canonical names", func() {
+		level := lvlInfo
+		flag := New(&level, "level", levelIdentifiersTest, EnumCaseInsensitive, WithNumericCodes())
+		Expect(flag.Set("0x1f")).To(Succeed())
+		Expect(flag.String()).To(Equal("fatal"))
+	})
+
+	It("sets slice elements from codes", func() {
+		var levels []levelTest
+		flag := NewSlice(&levels, "levels", levelIdentifiersTest, EnumCaseInsensitive, WithNumericCodes())
+		Expect(flag.Set("-4,warn,0x1f")).To(Succeed())
+		Expect(levels).To(ConsistOf(lvlDebug, lvlWarn, lvlFatal))
+		Expect(flag.String()).To(Equal("[debug,warn,fatal]"))
+
+		err := flag.Set("info,1")
+		Expect(err).To(HaveOccurred())
+		var ierr *InvalidValueError
+		Expect(err).To(BeAssignableToTypeOf(ierr))
+		ierr = err.(*InvalidValueError)
+		Expect(ierr.Input).To(Equal("1"))
+		Expect(ierr.Index).To(Equal(1))
+	})
+
+	It("panics for non-integer enum types", func() {
+		s := "foo"
+		Expect(func() {
+			_ = New(&s, "s", EnumIdentifiers[string]{"foo": {"foo"}}, EnumCaseInsensitive, WithNumericCodes())
+		}).To(PanicWith(MatchRegexp(`numeric codes require an integer enum type, but string isn't`)))
+	})
+
+})
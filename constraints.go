@@ -0,0 +1,315 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConstraintKind identifies the kind of constraint of slice enum flags that has
+// been violated.
+type ConstraintKind uint8
+
+// Kinds of slice enum flag constraints; see [WithMinCount], [WithMaxCount],
+// [WithMaxInputSize], [WithMutuallyExclusive], [WithStandalone],
+// [RejectDuplicates], and [RejectRepeats].
+const (
+	MinCountConstraint ConstraintKind = iota + 1
+	MaxCountConstraint
+	MaxInputSizeConstraint
+	MutuallyExclusiveConstraint
+	StandaloneConstraint
+	DuplicateConstraint
+	RepeatConstraint
+)
+
+// ConstraintError is returned when setting a slice enum flag would violate one
+// of its constraints. Use [errors.As] to get hold of the details in order to
+// render your own diagnostics.
+type ConstraintError struct {
+	// Type is the name of the enum flag value type, as returned by
+	// [EnumFlagValue.Type].
+	Type string
+	// Kind of the violated constraint.
+	Kind ConstraintKind
+	// Limit is the minimum or maximum number of enum values, or the maximum
+	// input size in bytes; it is zero for mutually exclusive enum values.
+	Limit int
+	// Actual is the actual number of enum values, or the actual input size in
+	// bytes; it is zero for mutually exclusive enum values.
+	Actual int
+	// Exclusive lists the canonical names of the mutually exclusive enum
+	// values that were specified together. For standalone enum values, the
+	// standalone enum value comes first, followed by the other enum values.
+	Exclusive []string
+	// Duplicate is the canonical name of a duplicate enum value.
+	Duplicate string
+
+	reason string // pre-rendered error message
+}
+
+// Error returns the error message describing the violated constraint.
+func (e *ConstraintError) Error() string { return e.reason }
+
+// WithMinCount requires slice enum flags to have at least the specified number
+// of enum values after each Set. The enum flag value constructors panic if the
+// minimum count is negative or larger than a maximum count.
+func WithMinCount(n int) Option {
+	return func(o *options) {
+		o.minCount = n
+	}
+}
+
+// WithMaxCount allows slice enum flags to have at most the specified number of
+// enum values. Completion stops offering further enum values when the maximum
+// count has been reached. A maximum count of zero disables this limit. The
+// enum flag value constructors panic if the maximum count is negative.
+func WithMaxCount(n int) Option {
+	return func(o *options) {
+		o.maxCount = n
+	}
+}
+
+// WithMaxInputSize rejects textual representations of slice enum flags longer
+// than the specified number of bytes before even trying to parse them, in
+// order to protect against huge inputs. A maximum input size of zero disables
+// this limit. The enum flag value constructors panic if the maximum input size
+// is negative.
+func WithMaxInputSize(n int) Option {
+	return func(o *options) {
+		o.maxInputSize = n
+	}
+}
+
+// WithMutuallyExclusive declares the specified enum values of slice enum flags
+// to be pairwise mutually exclusive, so at most one of them can be set at the
+// same time, such as either “fast” or “thorough”. This option can be passed
+// multiple times to declare multiple sets of mutually exclusive enum values.
+// Completion doesn't offer enum values that are mutually exclusive with
+// already specified enum values. In order to declare a “none” enum value that
+// cannot be combined with any other enum value, use [WithStandalone] instead.
+//
+// The enum flag value constructors panic if an enum value isn't mapped, or if
+// it isn't of the enum type of the flag value.
+func WithMutuallyExclusive[E comparable](enumvals ...E) Option {
+	return func(o *options) {
+		set := make([]any, 0, len(enumvals))
+		for _, enumval := range enumvals {
+			set = append(set, enumval)
+		}
+		o.exclusive = append(o.exclusive, set)
+	}
+}
+
+// WithStandalone declares the specified enum values of slice enum flags to
+// stand alone, so that they cannot be combined with any other enum value, such
+// as a “none” enum value. Completion doesn't offer standalone enum values when
+// other enum values have already been specified, and vice versa.
+//
+// The enum flag value constructors panic if an enum value isn't mapped, or if
+// it isn't of the enum type of the flag value.
+func WithStandalone[E comparable](enumvals ...E) Option {
+	return func(o *options) {
+		for _, enumval := range enumvals {
+			o.standalone = append(o.standalone, enumval)
+		}
+	}
+}
+
+// constraints of slice enum flags.
+type constraints[E comparable] struct {
+	minCount     int   // zero for no minimum
+	maxCount     int   // zero for no maximum
+	maxInputSize int   // zero for no maximum
+	exclusive    [][]E // sets of mutually exclusive enum values
+	standalone   []E   // enum values not to be combined with others
+}
+
+// newConstraints returns the constraints configured in the specified options.
+func newConstraints[E comparable](o options) constraints[E] {
+	c := constraints[E]{
+		minCount:     o.minCount,
+		maxCount:     o.maxCount,
+		maxInputSize: o.maxInputSize,
+	}
+	for _, set := range o.exclusive {
+		enumvals := make([]E, 0, len(set))
+		for _, enumval := range set {
+			if enumval, ok := enumval.(E); ok {
+				enumvals = append(enumvals, enumval)
+			}
+		}
+		c.exclusive = append(c.exclusive, enumvals)
+	}
+	for _, enumval := range o.standalone {
+		if enumval, ok := enumval.(E); ok {
+			c.standalone = append(c.standalone, enumval)
+		}
+	}
+	return c
+}
+
+// validateConstraints checks the constraints in the specified options for
+// problems, returning an error describing all problems found, if any.
+func validateConstraints[E comparable](mapping EnumIdentifiers[E], o options) error {
+	problems := []string{}
+	if o.minCount < 0 {
+		problems = append(problems, fmt.Sprintf("minimum count %d is negative", o.minCount))
+	}
+	if o.maxCount < 0 {
+		problems = append(problems, fmt.Sprintf("maximum count %d is negative", o.maxCount))
+	}
+	if o.maxCount > 0 && o.minCount > o.maxCount {
+		problems = append(problems, fmt.Sprintf("minimum count %d exceeds maximum count %d",
+			o.minCount, o.maxCount))
+	}
+	if o.maxInputSize < 0 {
+		problems = append(problems, fmt.Sprintf("maximum input size %d is negative", o.maxInputSize))
+	}
+	for _, set := range o.exclusive {
+		for _, enumval := range set {
+			if problem := checkEnumValue("mutually exclusive", enumval, mapping); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	for _, enumval := range o.standalone {
+		if problem := checkEnumValue("standalone", enumval, mapping); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	return problemsError(problems)
+}
+
+// checkInput returns a *ConstraintError if the textual representation exceeds
+// the maximum input size, otherwise nil.
+func (c constraints[E]) checkInput(val string) error {
+	if c.maxInputSize == 0 || len(val) <= c.maxInputSize {
+		return nil
+	}
+	return &ConstraintError{
+		Kind:   MaxInputSizeConstraint,
+		Limit:  c.maxInputSize,
+		Actual: len(val),
+		reason: fmt.Sprintf("input must not exceed %d bytes, but has %d bytes",
+			c.maxInputSize, len(val)),
+	}
+}
+
+// check returns a *ConstraintError if the specified enum values violate any
+// constraint, otherwise nil.
+func (c constraints[E]) check(enumvals []E, names enumMapper[E]) error {
+	if len(enumvals) < c.minCount {
+		return &ConstraintError{
+			Kind:   MinCountConstraint,
+			Limit:  c.minCount,
+			Actual: len(enumvals),
+			reason: fmt.Sprintf("requires at least %d value(s), but got %d",
+				c.minCount, len(enumvals)),
+		}
+	}
+	return c.checkDefaults(enumvals, names)
+}
+
+// checkDefaults returns a *ConstraintError if the specified enum values
+// violate any constraint except for the minimum count, otherwise nil. Default
+// enum values don't need to satisfy the minimum count, as they might be
+// deliberately empty.
+func (c constraints[E]) checkDefaults(enumvals []E, names enumMapper[E]) error {
+	if c.maxCount > 0 && len(enumvals) > c.maxCount {
+		return &ConstraintError{
+			Kind:   MaxCountConstraint,
+			Limit:  c.maxCount,
+			Actual: len(enumvals),
+			reason: fmt.Sprintf("allows at most %d value(s), but got %d",
+				c.maxCount, len(enumvals)),
+		}
+	}
+	for _, set := range c.exclusive {
+		exclusive := []string{}
+		for _, enumval := range set {
+			if slices.Contains(enumvals, enumval) {
+				exclusive = append(exclusive, canonical(enumval, names))
+			}
+		}
+		if len(exclusive) > 1 {
+			return &ConstraintError{
+				Kind:      MutuallyExclusiveConstraint,
+				Exclusive: exclusive,
+				reason:    fmt.Sprintf("'%s' are mutually exclusive", strings.Join(exclusive, "', '")),
+			}
+		}
+	}
+	for _, enumval := range c.standalone {
+		if len(enumvals) < 2 || !slices.Contains(enumvals, enumval) {
+			continue
+		}
+		exclusive := []string{canonical(enumval, names)}
+		for _, other := range enumvals {
+			if other != enumval {
+				exclusive = append(exclusive, canonical(other, names))
+			}
+		}
+		return &ConstraintError{
+			Kind:      StandaloneConstraint,
+			Exclusive: exclusive,
+			reason: fmt.Sprintf("'%s' cannot be combined with '%s'",
+				exclusive[0], strings.Join(exclusive[1:], "', '")),
+		}
+	}
+	return nil
+}
+
+// limitCompletion returns true if there are any constraints limiting the enum
+// values offered in completions.
+func (c constraints[E]) limitCompletion() bool {
+	return c.maxCount > 0 || len(c.exclusive) > 0 || len(c.standalone) > 0
+}
+
+// completable returns true if adding the specified candidate enum value to the
+// present enum values doesn't violate the maximum count, mutual exclusivity, or
+// standalone constraints.
+func (c constraints[E]) completable(present []E, candidate E) bool {
+	if c.maxCount > 0 && len(present) >= c.maxCount {
+		return false
+	}
+	others := slices.ContainsFunc(present, func(enumval E) bool { return enumval != candidate })
+	if others && (slices.Contains(c.standalone, candidate) ||
+		slices.ContainsFunc(present, func(enumval E) bool { return slices.Contains(c.standalone, enumval) })) {
+		return false
+	}
+	for _, set := range c.exclusive {
+		if !slices.Contains(set, candidate) {
+			continue
+		}
+		if slices.ContainsFunc(present, func(enumval E) bool {
+			return enumval != candidate && slices.Contains(set, enumval)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// canonical returns the canonical name of the specified enum value, or
+// "<unknown>" if the enum value isn't mapped.
+func canonical[E comparable](enumval E, names enumMapper[E]) string {
+	if ids := names.Lookup(enumval); len(ids) > 0 {
+		return ids[0]
+	}
+	return unknown
+}
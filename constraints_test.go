@@ -0,0 +1,205 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("slice constraints", func() {
+
+	constraintError := func(err error) *ConstraintError {
+		GinkgoHelper()
+		var cerr *ConstraintError
+		Expect(errors.As(err, &cerr)).To(BeTrue(), "not a *ConstraintError: %v", err)
+		return cerr
+	}
+
+	It("enforces a minimum count", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := newFooModesTest(&foomodes, WithMinCount(2), WithEditOperators())
+		err := flag.Set("bar")
+		Expect(err).To(MatchError("requires at least 2 value(s), but got 1"))
+		cerr := constraintError(err)
+		Expect(cerr.Type).To(Equal("modes"))
+		Expect(cerr.Kind).To(Equal(MinCountConstraint))
+		Expect(cerr.Limit).To(Equal(2))
+		Expect(cerr.Actual).To(Equal(1))
+		Expect(foomodes).To(ConsistOf(fmFoo))
+
+		Expect(flag.Set("")).To(MatchError("requires at least 2 value(s), but got 0"))
+		Expect(flag.Set("+bar")).To(Succeed())
+		Expect(flag.Set("-bar")).NotTo(Succeed())
+		Expect(foomodes).To(ConsistOf(fmFoo, fmBar))
+	})
+
+	It("enforces a maximum count", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithMaxCount(2))
+		err := flag.Set("foo,bar,baz")
+		Expect(err).To(MatchError("allows at most 2 value(s), but got 3"))
+		cerr := constraintError(err)
+		Expect(cerr.Kind).To(Equal(MaxCountConstraint))
+		Expect(cerr.Limit).To(Equal(2))
+		Expect(cerr.Actual).To(Equal(3))
+		Expect(foomodes).To(BeEmpty())
+
+		Expect(flag.Set("foo,bar")).To(Succeed())
+		Expect(flag.Set("baz")).To(HaveOccurred())
+		Expect(foomodes).To(ConsistOf(fmFoo, fmBar))
+	})
+
+	It("enforces a maximum input size", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithMaxInputSize(8))
+		Expect(flag.Set("foo,bar")).To(Succeed())
+		err := flag.Set("foo,bar,baz")
+		Expect(err).To(MatchError("input must not exceed 8 bytes, but has 11 bytes"))
+		cerr := constraintError(err)
+		Expect(cerr.Kind).To(Equal(MaxInputSizeConstraint))
+		Expect(cerr.Limit).To(Equal(8))
+		Expect(cerr.Actual).To(Equal(11))
+	})
+
+	It("enforces mutually exclusive values", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes,
+			WithMutuallyExclusive(fmFoo, fmBar),
+			WithMutuallyExclusive(fmBar, fmBaz))
+		err := flag.Set("bar,baz,foo")
+		Expect(err).To(MatchError("'foo', 'bar' are mutually exclusive"))
+		cerr := constraintError(err)
+		Expect(cerr.Kind).To(Equal(MutuallyExclusiveConstraint))
+		Expect(cerr.Exclusive).To(Equal([]string{"foo", "bar"}))
+
+		Expect(flag.Set("foo,baz")).To(Succeed())
+		Expect(flag.Set("bar")).To(MatchError("'foo', 'bar' are mutually exclusive"))
+		Expect(foomodes).To(ConsistOf(fmFoo, fmBaz))
+	})
+
+	It("doesn't warn about deprecations on violations", func() {
+		var foomodes []FooModeTest
+		warnings := []string{}
+		flag := newFooModesTest(&foomodes,
+			WithMaxCount(1),
+			WithDeprecatedValue(fmBaz, "", ""),
+			WithWarningSink(func(warning string) { warnings = append(warnings, warning) }))
+		Expect(flag.Set("baz,foo")).NotTo(Succeed())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("completes only values not violating constraints", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithMaxCount(2), WithMutuallyExclusive(fmFoo, fmBar))
+		completor := flag.value.NewCompletor(flag.names, nil)
+
+		completions, _ := completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"foo", "bar", "Bar", "baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "foo,")
+		Expect(completions).To(Equal([]string{"foo,baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "foo,baz,")
+		Expect(completions).To(BeEmpty())
+
+		Expect(flag.Set("bar")).To(Succeed())
+		completions, _ = completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"bar", "Bar", "baz"}))
+	})
+
+	It("completes only edits not violating constraints", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := newFooModesTest(&foomodes, WithEditOperators(), WithMutuallyExclusive(fmFoo, fmBar))
+		completor := flag.value.NewCompletor(flag.names, nil)
+
+		completions, _ := completor(&cobra.Command{}, nil, "+")
+		Expect(completions).To(Equal([]string{"+baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "-foo,+")
+		Expect(completions).To(Equal([]string{"-foo,+bar", "-foo,+Bar", "-foo,+baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "baz,-")
+		Expect(completions).To(Equal([]string{"baz,-foo"}))
+	})
+
+	It("enforces standalone enum values", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithStandalone(fmFoo))
+		Expect(flag.Set("foo")).To(Succeed())
+		Expect(foomodes).To(ConsistOf(fmFoo))
+		Expect(flag.Set("bar")).To(MatchError("'foo' cannot be combined with 'bar'"))
+
+		foomodes = nil
+		flag = newFooModesTest(&foomodes, WithStandalone(fmFoo))
+		Expect(flag.Set("bar,baz")).To(Succeed())
+		err := flag.Set("bar,foo,baz")
+		Expect(err).To(MatchError("'foo' cannot be combined with 'bar', 'baz'"))
+		cerr := constraintError(err)
+		Expect(cerr.Kind).To(Equal(StandaloneConstraint))
+		Expect(cerr.Exclusive).To(Equal([]string{"foo", "bar", "baz"}))
+		Expect(foomodes).To(ConsistOf(fmBar, fmBaz))
+	})
+
+	It("completes only values not combined with standalone values", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithStandalone(fmFoo))
+		completor := flag.value.NewCompletor(flag.names, nil)
+
+		completions, _ := completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"foo", "bar", "Bar", "baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "foo,")
+		Expect(completions).To(BeEmpty())
+		completions, _ = completor(&cobra.Command{}, nil, "bar,")
+		Expect(completions).To(Equal([]string{"bar,baz"}))
+	})
+
+	It("panics on default enum values violating constraints", func() {
+		foomodes := []FooModeTest{fmFoo, fmBar, fmBaz}
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMaxCount(2)) }).To(
+			PanicWith(MatchRegexp(`NewSlice requires flag to reference enum values satisfying the constraints: allows at most 2 value\(s\), but got 3`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMutuallyExclusive(fmFoo, fmBar)) }).To(
+			PanicWith(MatchRegexp(`'foo', 'bar' are mutually exclusive`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithStandalone(fmBaz)) }).To(
+			PanicWith(MatchRegexp(`'baz' cannot be combined with 'foo', 'bar'`)))
+
+		foomodes = []FooModeTest{}
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMinCount(1)) }).NotTo(Panic())
+	})
+
+	It("disables limits of zero", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithMaxCount(0), WithMaxInputSize(0))
+		Expect(flag.Set("foo,bar,baz")).To(Succeed())
+		Expect(foomodes).To(HaveLen(3))
+	})
+
+	It("panics on invalid constraints", func() {
+		var foomodes []FooModeTest
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMinCount(-1)) }).To(
+			PanicWith(MatchRegexp(`minimum count -1 is negative`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMinCount(3), WithMaxCount(2)) }).To(
+			PanicWith(MatchRegexp(`minimum count 3 exceeds maximum count 2`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMaxInputSize(-1)) }).To(
+			PanicWith(MatchRegexp(`maximum input size -1 is negative`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMutuallyExclusive(FooModeTest(42))) }).To(
+			PanicWith(MatchRegexp(`mutually exclusive enum value 42 isn't mapped`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithMutuallyExclusive(42)) }).To(
+			PanicWith(MatchRegexp(`mutually exclusive enum value 42 is of type int instead of enumflag.FooModeTest`)))
+		Expect(func() { _ = newFooModesTest(&foomodes, WithStandalone(FooModeTest(42))) }).To(
+			PanicWith(MatchRegexp(`standalone enum value 42 isn't mapped`)))
+	})
+
+})
@@ -0,0 +1,96 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// deprecation describes why an identifier or enum value is deprecated, and
+// what to use instead.
+type deprecation struct {
+	message     string
+	replacement string // optional
+}
+
+// warning returns the deprecation warning for the specified user input.
+func (d deprecation) warning(input string) string {
+	w := fmt.Sprintf("'%s' is deprecated", input)
+	if d.message != "" {
+		w += ": " + d.message
+	}
+	if d.replacement != "" {
+		w += fmt.Sprintf("; use '%s' instead", d.replacement)
+	}
+	return w
+}
+
+// WithDeprecatedIdentifier marks the specified identifier as deprecated, with
+// a message explaining the deprecation and an optional replacement identifier.
+// Deprecated identifiers still get accepted, but emit a warning (see
+// [WithWarningSink]). Deprecated identifiers are neither offered in
+// completions nor listed in error messages.
+//
+// The enum flag value constructors panic if the identifier isn't mapped.
+func WithDeprecatedIdentifier(id string, message string, replacement string) Option {
+	return func(o *options) {
+		if o.deprecatedIds == nil {
+			o.deprecatedIds = map[string]deprecation{}
+		}
+		o.deprecatedIds[id] = deprecation{message: message, replacement: replacement}
+	}
+}
+
+// WithDeprecatedValue marks the specified enum value with all its identifiers
+// as deprecated, with a message explaining the deprecation and an optional
+// replacement identifier. Deprecated enum values still get accepted, but emit
+// a warning (see [WithWarningSink]). The identifiers of deprecated enum values
+// are neither offered in completions nor listed in error messages.
+//
+// The enum flag value constructors panic if the enum value isn't mapped, or
+// if it isn't of the enum type of the flag value.
+func WithDeprecatedValue[E comparable](enumval E, message string, replacement string) Option {
+	return func(o *options) {
+		if o.deprecatedValues == nil {
+			o.deprecatedValues = map[any]deprecation{}
+		}
+		o.deprecatedValues[enumval] = deprecation{message: message, replacement: replacement}
+	}
+}
+
+// WithWarningSink passes warnings, such as when using deprecated identifiers,
+// to the specified sink instead of writing them to stderr.
+func WithWarningSink(sink func(warning string)) Option {
+	return func(o *options) {
+		o.warn = sink
+	}
+}
+
+// WithCommandWarnings writes warnings, such as when using deprecated
+// identifiers, to the stderr of the specified cobra command, as returned by
+// [cobra.Command.ErrOrStderr] at the time of the warning.
+func WithCommandWarnings(cmd *cobra.Command) Option {
+	return WithWarningSink(func(warning string) {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: "+warning)
+	})
+}
+
+// warnStderr is the default warning sink, writing warnings to stderr.
+func warnStderr(warning string) {
+	fmt.Fprintln(os.Stderr, "Warning: "+warning)
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"bytes"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deprecations", func() {
+
+	mapping := EnumIdentifiers[FooModeTest]{
+		fmFoo: {"foo", "fu"},
+		fmBar: {"bar", "Bar"},
+		fmBaz: {"baz"},
+	}
+
+	var warnings []string
+	var sink Option
+
+	BeforeEach(func() {
+		warnings = nil
+		sink = WithWarningSink(func(warning string) {
+			warnings = append(warnings, warning)
+		})
+	})
+
+	It("renders warnings", func() {
+		Expect(deprecation{}.warning("foo")).To(Equal("'foo' is deprecated"))
+		Expect(deprecation{message: "it's gone"}.warning("foo")).To(
+			Equal("'foo' is deprecated: it's gone"))
+		Expect(deprecation{message: "it's gone", replacement: "bar"}.warning("foo")).To(
+			Equal("'foo' is deprecated: it's gone; use 'bar' instead"))
+	})
+
+	It("warns about deprecated identifiers", func() {
+		foomode := fmBar
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive, sink,
+			WithDeprecatedIdentifier("fu", "misspelled", "foo"))
+		Expect(flag.Set("foo")).To(Succeed())
+		Expect(warnings).To(BeEmpty())
+		Expect(flag.Set("FU")).To(Succeed())
+		Expect(foomode).To(Equal(fmFoo))
+		Expect(warnings).To(ConsistOf("'FU' is deprecated: misspelled; use 'foo' instead"))
+	})
+
+	It("warns about deprecated values", func() {
+		foomode := fmFoo
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive, sink,
+			WithDeprecatedValue(fmBar, "no more bars", "baz"))
+		Expect(flag.Set("Bar")).To(Succeed())
+		Expect(foomode).To(Equal(fmBar))
+		Expect(flag.String()).To(Equal("bar"))
+		Expect(warnings).To(ConsistOf("'Bar' is deprecated: no more bars; use 'baz' instead"))
+	})
+
+	It("warns about abbreviated deprecated identifiers", func() {
+		foomode := fmFoo
+		flag := New(&foomode, "mode", mapping, EnumCaseSensitive, sink,
+			WithAbbreviations(),
+			WithDeprecatedIdentifier("Bar", "use lower case", ""),
+			WithDeprecatedIdentifier("baz", "baz is gone", ""))
+		Expect(flag.Set("B")).To(Succeed())
+		Expect(foomode).To(Equal(fmBar))
+		Expect(flag.Set("ba")).To(MatchError(ContainSubstring("ambiguous")))
+		Expect(warnings).To(ConsistOf("'B' is deprecated: use lower case"))
+		warnings = nil
+		Expect(flag.Set("baz")).To(Succeed())
+		Expect(warnings).To(ConsistOf("'baz' is deprecated: baz is gone"))
+	})
+
+	It("warns about deprecated slice elements only after success", func() {
+		var foomodes []FooModeTest
+		flag := NewSlice(&foomodes, "modes", mapping, EnumCaseInsensitive, sink,
+			WithDeprecatedIdentifier("fu", "misspelled", "foo"),
+			WithDeprecatedValue(fmBaz, "", ""))
+		Expect(flag.Set("fu,bar,zoo")).NotTo(Succeed())
+		Expect(warnings).To(BeEmpty())
+		Expect(flag.Set("fu,bar,baz")).To(Succeed())
+		Expect(foomodes).To(ConsistOf(fmFoo, fmBar, fmBaz))
+		Expect(warnings).To(ConsistOf(
+			"'fu' is deprecated: misspelled; use 'foo' instead",
+			"'baz' is deprecated"))
+	})
+
+	It("leaves deprecated identifiers out of errors and suggestions", func() {
+		foomode := fmFoo
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive, sink,
+			WithDeprecatedIdentifier("fu", "", ""),
+			WithDeprecatedValue(fmBaz, "", ""))
+		err := flag.Set("fooo")
+		Expect(err).To(MatchError("must be 'bar'/'Bar', 'foo'; did you mean 'foo'?"))
+		Expect(err.(*InvalidValueError).Allowed).To(ConsistOf("bar", "foo"))
+		Expect(flag.Set("bax")).To(MatchError("must be 'bar'/'Bar', 'foo'; did you mean 'bar'?"))
+	})
+
+	It("leaves deprecated identifiers out of completions", func() {
+		foomode := fmFoo
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithDeprecatedIdentifier("fu", "", ""),
+			WithDeprecatedValue(fmBaz, "", ""))
+		completions, _ := flag.value.NewCompletor(flag.names, nil)(&cobra.Command{}, nil, "")
+		Expect(completions).To(ConsistOf("foo", "bar", "Bar"))
+
+		var foomodes []FooModeTest
+		slice := NewSlice(&foomodes, "modes", mapping, EnumCaseInsensitive,
+			WithDeprecatedIdentifier("fu", "", ""),
+			WithDeprecatedValue(fmBaz, "", ""))
+		completions, _ = slice.value.NewCompletor(slice.names, nil)(&cobra.Command{}, nil, "bar,")
+		Expect(completions).To(ConsistOf("bar,foo"))
+	})
+
+	It("writes warnings to a cobra command's stderr", func() {
+		var stderr bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetErr(&stderr)
+		foomode := fmFoo
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithCommandWarnings(cmd),
+			WithDeprecatedIdentifier("fu", "misspelled", "foo"))
+		Expect(flag.Set("fu")).To(Succeed())
+		Expect(stderr.String()).To(Equal("Warning: 'fu' is deprecated: misspelled; use 'foo' instead\n"))
+	})
+
+	It("panics on invalid deprecations", func() {
+		foomode := fmFoo
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithDeprecatedIdentifier("fuu", "", ""))
+		}).To(PanicWith(MatchRegexp(`deprecated identifier 'fuu' isn't mapped`)))
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithDeprecatedValue(FooModeTest(42), "", ""))
+		}).To(PanicWith(MatchRegexp(`deprecated enum value 42 isn't mapped`)))
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithDeprecatedValue(42, "", ""))
+		}).To(PanicWith(MatchRegexp(`deprecated enum value 42 is of type int instead of enumflag.FooModeTest`)))
+	})
+
+})
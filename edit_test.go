@@ -0,0 +1,101 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("slice edit operators", func() {
+
+	DescribeTable("edits the current values",
+		func(edits []string, expected []FooModeTest) {
+			foomodes := []FooModeTest{fmFoo, fmBar}
+			flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithEditOperators())
+			for _, edit := range edits {
+				Expect(flag.Set(edit)).To(Succeed())
+			}
+			Expect(foomodes).To(Equal(expected))
+		},
+		Entry(nil, []string{"+baz"}, []FooModeTest{fmFoo, fmBar, fmBaz}),
+		Entry(nil, []string{"-bar"}, []FooModeTest{fmFoo}),
+		Entry(nil, []string{"+baz,-Bar"}, []FooModeTest{fmFoo, fmBaz}),
+		Entry(nil, []string{"+foo"}, []FooModeTest{fmFoo, fmBar}),
+		Entry(nil, []string{"-baz"}, []FooModeTest{fmFoo, fmBar}),
+		Entry(nil, []string{"baz,-foo"}, []FooModeTest{fmBar, fmBaz}),
+		Entry(nil, []string{""}, []FooModeTest{}),
+		Entry(nil, []string{"", "+baz"}, []FooModeTest{fmBaz}),
+		Entry(nil, []string{"baz"}, []FooModeTest{fmBaz}),
+		Entry(nil, []string{"-foo", "bar,baz"}, []FooModeTest{fmBar, fmBaz}),
+		Entry(nil, []string{"baz", "-baz,+foo"}, []FooModeTest{fmFoo}),
+	)
+
+	It("reports the offset of invalid edits", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithEditOperators())
+		err := flag.Set("+bar,-fool")
+		Expect(err).To(HaveOccurred())
+		ierr := err.(*InvalidValueError)
+		Expect(ierr.Input).To(Equal("fool"))
+		Expect(ierr.Index).To(Equal(1))
+		Expect(ierr.Offset).To(Equal(6))
+		Expect(foomodes).To(ConsistOf(fmFoo))
+	})
+
+	It("doesn't edit unless enabled", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("+bar")).NotTo(Succeed())
+		Expect(flag.Set("")).NotTo(Succeed())
+	})
+
+	It("completes edits", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithEditOperators(),
+			WithOrder(fmFoo, fmBar, fmBaz))
+		completor := flag.value.NewCompletor(flag.names, Help[FooModeTest]{fmBaz: "bazz"})
+
+		completions, _ := completor(&cobra.Command{}, nil, "+")
+		Expect(completions).To(Equal([]string{"+bar", "+Bar", "+baz\tbazz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "-")
+		Expect(completions).To(Equal([]string{"-foo"}))
+		completions, _ = completor(&cobra.Command{}, nil, "+bar,+")
+		Expect(completions).To(Equal([]string{"+bar,+baz\tbazz"}))
+		completions, directive := completor(&cobra.Command{}, nil, "+baz,")
+		Expect(completions).To(Equal([]string{"+baz,foo", "+baz,bar", "+baz,Bar", "+baz,baz\tbazz"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp |
+			cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveKeepOrder))
+
+		Expect(flag.Set("+bar")).To(Succeed())
+		completions, _ = completor(&cobra.Command{}, nil, "-")
+		Expect(completions).To(Equal([]string{"-foo", "-bar", "-Bar"}))
+	})
+
+	It("panics on identifiers with edit operators", func() {
+		var foomodes []FooModeTest
+		Expect(func() {
+			_ = NewSlice(&foomodes, "modes", EnumIdentifiers[FooModeTest]{
+				fmFoo: {"+foo"},
+			}, EnumCaseInsensitive, WithEditOperators())
+		}).To(PanicWith(MatchRegexp(`identifier '\+foo' of enum value 1 starts with an edit operator`)))
+	})
+
+})
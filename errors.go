@@ -0,0 +1,53 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+// InvalidValueError is returned when setting an enum flag from a textual
+// representation that doesn't match any of the enum identifiers (or matches
+// more than one in case of abbreviations). Use [errors.As] to get hold of the
+// details in order to render your own diagnostics.
+type InvalidValueError struct {
+	// Type is the name of the enum flag value type, as returned by
+	// [EnumFlagValue.Type].
+	Type string
+	// Input is the offending textual representation; in case of slice enum
+	// flags, this is only the offending element.
+	Input string
+	// Allowed lists the canonical names of all enum values in their declared
+	// order (see [WithOrder]), otherwise sorted alphabetically; hidden and
+	// deprecated identifiers and enum values aren't listed.
+	Allowed []string
+	// Candidates lists the identifiers matching an ambiguous abbreviation,
+	// sorted alphabetically; it is empty if Input isn't an ambiguous
+	// abbreviation.
+	Candidates []string
+	// Suggestions lists the identifiers closest to Input, closest first.
+	Suggestions []string
+	// Index is the (zero-based) index of the offending element in case of
+	// slice enum flags, or -1 otherwise.
+	Index int
+	// Offset is the byte offset of the offending element inside the complete
+	// separated textual representation in case of slice enum flags;
+	// otherwise, it is always zero. For quoted elements, the offset is that
+	// of the element text after the opening quote.
+	Offset int
+
+	reason string // pre-rendered error message
+}
+
+// Error returns the error message, which either lists all the textual
+// representations that are valid or the candidates for an ambiguous
+// abbreviation.
+func (e *InvalidValueError) Error() string { return e.reason }
@@ -15,7 +15,9 @@
 package enumflag
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -41,11 +43,31 @@ type EnumCaseSensitivity bool
 
 // Controls whether the textual representations for enum values are case
 // sensitive, or not.
+//
+// EnumCaseInsensitive compares the lower-case forms of textual
+// representations. While this works fine with ASCII-only identifiers, it
+// mishandles some non-ASCII identifiers. For non-ASCII identifiers, either
+// [EnumCaseFold] or [EnumCaseFoldFull] should be used instead.
 const (
 	EnumCaseInsensitive EnumCaseSensitivity = false
 	EnumCaseSensitive   EnumCaseSensitivity = true
 )
 
+// EnumCaseFolding specifies how the textual representations of enum values
+// are matched case insensitively using Unicode case folding.
+type EnumCaseFolding uint8
+
+// Unicode case folding modes.
+//
+// EnumCaseFold applies simple Unicode case folding, matching the same
+// identifiers as [strings.EqualFold] does. EnumCaseFoldFull additionally
+// applies the Unicode case foldings that change the length of identifiers,
+// such as matching “straße” with “STRASSE”.
+const (
+	EnumCaseFold EnumCaseFolding = iota + 1
+	EnumCaseFoldFull
+)
+
 // EnumFlagValue wraps a user-defined enum type value satisfying comparable or
 // []comparable. It implements the [github.com/spf13/pflag.Value] interface, so
 // the user-defined enum type value can directly be used with the fine pflag
@@ -65,20 +87,27 @@ type EnumFlagValue[E comparable] struct {
 // perfectness! Strike!
 type enumValue[E comparable] interface {
 	Get() any
+	Reset()
 	Set(val string, names enumMapper[E]) error
 	String(names enumMapper[E]) string
-	NewCompletor(enums EnumIdentifiers[E], help Help[E]) Completor
+	NewCompletor(names enumMapper[E], help Help[E]) Completor
 }
 
 // New wraps a given enum variable (satisfying the predeclared type identifier
 // comparable) so that it can be used as a flag Value with
 // [github.com/spf13/pflag.Var] and [github.com/spf13/pflag.VarP]. In case no
 // default enum value should be set and therefore no default shown in
-// [spf13/cobra], use [NewWithoutDefault] instead.
+// [spf13/cobra], use [NewWithoutDefault] instead. User input gets matched to
+// the enum identifiers using the specified [Matcher], such as
+// [EnumCaseInsensitive] or [MatchSmartCase]. Optional behavior can be enabled by
+// additionally passing options, such as [WithAbbreviations].
+//
+// New panics if the mapping isn't valid (see [Validate]), the matcher is nil,
+// or if the enum variable doesn't reference a mapped enum value.
 //
 // [spf13/cobra]: https://github.com/spf13/cobra
-func New[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], sensitivity EnumCaseSensitivity) *EnumFlagValue[E] {
-	return new("New", flag, typename, mapping, sensitivity, false)
+func New[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumFlagValue[E] {
+	return new("New", flag, typename, mapping, matcher, false, opts)
 }
 
 // NewWithoutDefault wraps a given enum variable (satisfying the predeclared
@@ -90,48 +119,240 @@ func New[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], sen
 // [spf13/cobra] won't show any default value in its help for CLI enum flags
 // created with NewWithoutDefault.
 //
+// NewWithoutDefault panics if the mapping isn't valid (see [Validate]), maps
+// the zero enum value, or if the enum variable references an enum value that
+// is neither zero nor mapped.
+//
 // [spf13/cobra]: https://github.com/spf13/cobra
-func NewWithoutDefault[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], sensitivity EnumCaseSensitivity) *EnumFlagValue[E] {
-	return new("NewWithoutDefault", flag, typename, mapping, sensitivity, true)
+func NewWithoutDefault[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumFlagValue[E] {
+	return new("NewWithoutDefault", flag, typename, mapping, matcher, true, opts)
+}
+
+// NewOptional wraps a given pointer to an enum variable (satisfying the
+// predeclared type identifier comparable) so that it can be used as a flag
+// Value with [github.com/spf13/pflag.Var] and [github.com/spf13/pflag.VarP].
+// The pointer stays nil until the flag gets set, so that all mapped enum
+// values, including the zero enum value, are legitimate choices. Contrary to
+// [NewWithoutDefault], the zero enum value thus may be mapped. [spf13/cobra]
+// won't show any default value in its help for CLI enum flags created with
+// NewOptional. Use [EnumFlagValue.GetOptionalValue] to get the enum value
+// together with whether it has been set.
+//
+// NewOptional panics if the mapping isn't valid (see [Validate]), the matcher
+// is nil, or if the pointer to the enum variable isn't nil.
+//
+// [spf13/cobra]: https://github.com/spf13/cobra
+func NewOptional[E comparable](flag **E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumFlagValue[E] {
+	if flag == nil {
+		panic("NewOptional requires flag to be a non-nil pointer to a pointer to an enum value satisfying comparable")
+	}
+	if mapping == nil {
+		panic("NewOptional requires mapping not to be nil")
+	}
+	if err := validate(mapping, matcher, newOptions(opts)); err != nil {
+		panic(fmt.Sprintf("NewOptional requires a valid mapping: %s", err))
+	}
+	if *flag != nil {
+		panic("NewOptional requires flag to reference a nil pointer to an enum value")
+	}
+	return &EnumFlagValue[E]{
+		value:    &enumOptional[E]{v: flag},
+		enumtype: typename,
+		names:    newEnumMapper(mapping, matcher, opts...),
+	}
 }
 
 // new returns a new enum variable to be used with pflag.Var and pflag.VarP.
-func new[E comparable](ctor string, flag *E, typename string, mapping EnumIdentifiers[E], sensitivity EnumCaseSensitivity, nodefault bool) *EnumFlagValue[E] {
+func new[E comparable](ctor string, flag *E, typename string, mapping EnumIdentifiers[E], matcher Matcher, nodefault bool, opts []Option) *EnumFlagValue[E] {
 	if flag == nil {
 		panic(fmt.Sprintf("%s requires flag to be a non-nil pointer to an enum value satisfying comparable", ctor))
 	}
 	if mapping == nil {
 		panic(fmt.Sprintf("%s requires mapping not to be nil", ctor))
 	}
+	if err := validate(mapping, matcher, newOptions(opts)); err != nil {
+		panic(fmt.Sprintf("%s requires a valid mapping: %s", ctor, err))
+	}
+	var zero E
+	if nodefault {
+		if _, ok := mapping[zero]; ok {
+			panic(fmt.Sprintf("%s requires the zero enum value not to be mapped", ctor))
+		}
+	}
+	if _, ok := mapping[*flag]; !ok && !(nodefault && *flag == zero) {
+		panic(fmt.Sprintf("%s requires flag to reference a mapped enum value, but %v isn't mapped",
+			ctor, *flag))
+	}
 	return &EnumFlagValue[E]{
-		value:    &enumScalar[E]{v: flag, nodefault: nodefault},
+		value:    &enumScalar[E]{v: flag, nodefault: nodefault, def: *flag},
 		enumtype: typename,
-		names:    newEnumMapper(mapping, sensitivity),
+		names:    newEnumMapper(mapping, matcher, opts...),
 	}
 }
 
 // NewSlice wraps a given enum slice variable (satisfying [comparable])
 // so that it can be used as a flag Value with [github.com/spf13/pflag.Var] and
-// [github.com/spf13/pflag.VarP].
-func NewSlice[E comparable](flag *[]E, typename string, mapping EnumIdentifiers[E], sensitivity EnumCaseSensitivity) *EnumFlagValue[E] {
+// [github.com/spf13/pflag.VarP]. Optional behavior can be enabled by
+// additionally passing options, such as [WithAbbreviations].
+//
+// NewSlice panics if the mapping isn't valid (see [Validate]), the matcher is
+// nil, the mapping contains identifiers with the separator (see
+// [WithSeparator] and [WithQuoting]), the constraints or policies aren't
+// valid, or if the enum slice variable references unmapped enum values or
+// violates the constraints, except for the minimum count. The enum slice
+// variable gets adjusted to the duplicate and order policies, if any; see
+// [WithDuplicates] and [WithOrderPolicy].
+func NewSlice[E comparable](flag *[]E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumSliceFlagValue[E] {
 	if flag == nil {
 		panic("NewSlice requires flag to be a non-nil pointer to an enum value slice satisfying []any")
 	}
+	slice, names := newSlice("NewSlice", flag, mapping, matcher, opts)
+	return &EnumSliceFlagValue[E]{
+		EnumFlagValue: EnumFlagValue[E]{
+			value:    slice,
+			enumtype: typename,
+			names:    names,
+		},
+		slice: slice,
+	}
+}
+
+// NewSet wraps a given [EnumSet] variable so that it can be used as a flag
+// Value with [github.com/spf13/pflag.Var] and [github.com/spf13/pflag.VarP].
+// Set enum flags share the parsing, options, and completion of slice enum
+// flags (see [NewSlice]), but duplicate enum values are always dropped, unless
+// rejected (see [WithDuplicates]), and the textual representation is always
+// in mapping order.
+//
+// NewSet panics for the same reasons as [NewSlice] does.
+func NewSet[E comparable](flag *EnumSet[E], typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumFlagValue[E] {
+	if flag == nil {
+		panic("NewSet requires flag to be a non-nil pointer to an enum set")
+	}
+	set := &enumSet[E]{v: flag}
+	set.shadow = flag.Values()
+	opts = append(slices.Clone(opts), WithOrderPolicy(MappingOrder))
+	if newOptions(opts).duplicates != RejectDuplicates {
+		opts = append(opts, WithDuplicates(DropDuplicates))
+	}
+	slice, names := newSlice("NewSet", &set.shadow, mapping, matcher, opts)
+	set.slice = slice
+	set.order, _ = names.Order()
+	set.sync()
+	return &EnumFlagValue[E]{
+		value:    set,
+		enumtype: typename,
+		names:    names,
+	}
+}
+
+// newSlice returns a new slice enum value for the specified enum slice
+// variable, together with its mapper.
+func newSlice[E comparable](ctor string, flag *[]E, mapping EnumIdentifiers[E], matcher Matcher, opts []Option) (*enumSlice[E], enumMapper[E]) {
 	if mapping == nil {
-		panic("NewSlice requires mapping not to be nil")
+		panic(fmt.Sprintf("%s requires mapping not to be nil", ctor))
+	}
+	o := newOptions(opts)
+	if err := errors.Join(
+		validate(mapping, matcher, o),
+		validateListSyntax(mapping, o),
+		validateConstraints(mapping, o),
+		validatePolicies(*flag, o),
+	); err != nil {
+		panic(fmt.Sprintf("%s requires a valid mapping: %s", ctor, err))
+	}
+	for _, enumval := range *flag {
+		if _, ok := mapping[enumval]; !ok {
+			panic(fmt.Sprintf("%s requires flag to reference mapped enum values only, but %v isn't mapped",
+				ctor, enumval))
+		}
+	}
+	names := newEnumMapper(mapping, matcher, opts...)
+	policy := newSlicePolicy(o, names)
+	*flag = policy.normalize(*flag)
+	constraints := newConstraints[E](o)
+	if err := constraints.checkDefaults(*flag, names); err != nil {
+		panic(fmt.Sprintf("%s requires flag to reference enum values satisfying the constraints: %s",
+			ctor, err))
+	}
+	slice := &enumSlice[E]{
+		v:           flag,
+		def:         slices.Clone(*flag),
+		list:        listSeparator{sep: o.separator, quoting: o.quoting, operators: o.editOperators},
+		ignoreEmpty: o.emptyElements == IgnoreEmptyElements,
+		edit:        o.editOperators,
+		all:         o.all,
+		none:        o.none,
+		parseable:   o.parseable,
+
+		constraints: constraints,
+		policy:      policy,
+	}
+	return slice, names
+}
+
+// NewBitmask wraps a given integer-kinded enum variable so that it can be used
+// as a flag Value with [github.com/spf13/pflag.Var] and
+// [github.com/spf13/pflag.VarP], combining multiple enum values into a single
+// bitmask enum value. For instance, “--perm=read,write” as well as
+// “--perm=read|write” sets the enum variable to Read|Write. Mapping composite
+// enum values, such as “all” for Read|Write|Exec, is fine. An empty value clears
+// the bitmask. Optional behavior can be enabled by additionally passing
+// options, such as [WithAbbreviations].
+//
+// NewBitmask panics if the mapping isn't valid (see [Validate]), the matcher
+// is nil, the mapping contains identifiers with commas or vertical bars, or if
+// the enum variable has bits set that aren't covered by any mapped enum value.
+func NewBitmask[E Integer](flag *E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumFlagValue[E] {
+	if flag == nil {
+		panic("NewBitmask requires flag to be a non-nil pointer to an integer enum value")
+	}
+	if mapping == nil {
+		panic("NewBitmask requires mapping not to be nil")
+	}
+	if err := errors.Join(
+		validate(mapping, matcher, newOptions(opts)),
+		validateSeparator(mapping, ","),
+		validateSeparator(mapping, "|"),
+	); err != nil {
+		panic(fmt.Sprintf("NewBitmask requires a valid mapping: %s", err))
+	}
+	var mapped E
+	for enumval := range mapping {
+		mapped |= enumval
+	}
+	if unmapped := *flag &^ mapped; unmapped != 0 {
+		panic(fmt.Sprintf("NewBitmask requires flag to reference mapped bits only, but %#x isn't mapped",
+			unmapped))
 	}
 	return &EnumFlagValue[E]{
-		value:    &enumSlice[E]{v: flag},
+		value:    &enumBitmask[E]{v: flag, def: *flag, mapped: mapped},
 		enumtype: typename,
-		names:    newEnumMapper(mapping, sensitivity),
+		names:    newEnumMapper(mapping, matcher, opts...),
 	}
 }
 
 // Set sets the enum flag to the specified enum value. If the specified value
-// isn't a valid enum value, then the enum flag won't be set and an error is
-// returned instead.
+// isn't a valid enum value, then the enum flag won't be set and an
+// [*InvalidValueError] is returned instead. If the enum value would violate
+// the constraints of a slice enum flag, a [*ConstraintError] is returned
+// instead.
 func (e *EnumFlagValue[E]) Set(val string) error {
-	return e.value.Set(val, e.names)
+	return e.typed(e.value.Set(val, e.names))
+}
+
+// typed returns the specified error with the enum flag value type filled in,
+// if it is an [*InvalidValueError] or [*ConstraintError].
+func (e *EnumFlagValue[E]) typed(err error) error {
+	var ierr *InvalidValueError
+	if errors.As(err, &ierr) {
+		ierr.Type = e.enumtype
+	}
+	var cerr *ConstraintError
+	if errors.As(err, &cerr) {
+		cerr.Type = e.enumtype
+	}
+	return err
 }
 
 // String returns the textual representation of an enumeration (flag) value. In
@@ -144,6 +365,14 @@ func (e *EnumFlagValue[E]) String() string { return e.value.String(e.names) }
 // messages.
 func (e *EnumFlagValue[E]) Type() string { return e.enumtype }
 
+// Reset restores the enum value to its default at the time the enum flag value
+// was created, and forgets about any previous Set, so that the next Set again
+// is treated as the first one; see also [WithAccumulation]. When resetting
+// flags, for instance between multiple command executions in tests, make sure
+// to also reset the Changed field of the corresponding
+// [github.com/spf13/pflag.Flag].
+func (e *EnumFlagValue[E]) Reset() { e.value.Reset() }
+
 // Get returns the current enum value for convenience. Please note that the enum
 // value is either scalar or slice, depending on how the enum flag was created.
 func (e *EnumFlagValue[E]) Get() any { return e.value.Get() }
@@ -152,9 +381,15 @@ func (e *EnumFlagValue[E]) Get() any { return e.value.Get() }
 // optional help texts.
 func (e *EnumFlagValue[E]) RegisterCompletion(cmd *cobra.Command, name string, help Help[E]) error {
 	return cmd.RegisterFlagCompletionFunc(
-		name, e.value.NewCompletor(e.names.Mapping(), help))
+		name, e.value.NewCompletor(e.names, help))
 }
 
+// Allowed returns the canonical names of all enum values in their declared
+// order (see [WithOrder]), otherwise sorted alphabetically, for use in help and
+// usage texts. Hidden and deprecated enum
+// identifiers and values aren't included.
+func (e *EnumFlagValue[E]) Allowed() []string { return slices.Clone(e.names.allowed) }
+
 // GetValue returns the (scalar) enum value of type E, otherwise it returns the
 // zero value for type E.
 func (e *EnumFlagValue[E]) GetValue() (v E) {
@@ -163,6 +398,25 @@ func (e *EnumFlagValue[E]) GetValue() (v E) {
 	return
 }
 
+// GetOptionalValue returns the optional enum value of type E and true if the
+// enum flag has been created using [NewOptional] and has been set. Otherwise,
+// it returns the zero value for type E and false.
+func (e *EnumFlagValue[E]) GetOptionalValue() (v E, ok bool) {
+	ev, _ := e.Get().(*E) // returns *E, not **E
+	if ev == nil {
+		return
+	}
+	return *ev, true
+}
+
+// GetSetValue returns the set enum value of type [EnumSet], otherwise it
+// returns the zero value for type EnumSet.
+func (e *EnumFlagValue[E]) GetSetValue() (v EnumSet[E]) {
+	ev := e.Get() // returns EnumSet[E], not *EnumSet[E]
+	v, _ = ev.(EnumSet[E])
+	return
+}
+
 // GetSliceValue returns the slice enum value of type []E, otherwise it returns
 // the zero value for type []E.
 func (e *EnumFlagValue[E]) GetSliceValue() (v []E) {
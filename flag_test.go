@@ -15,6 +15,8 @@
 package enumflag
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -33,13 +35,13 @@ var _ = Describe("flag", func() {
 		})
 
 		It("rejects setting invalid values", func() {
-			var foomode FooModeTest
+			foomode := fmFoo
 			val := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseSensitive)
 			Expect(val.Set("FOOBAR")).To(MatchError("must be 'bar'/'Bar', 'baz', 'foo'"))
 		})
 
 		It("sets the enumeration value from text", func() {
-			var foomode FooModeTest
+			foomode := fmFoo
 			val := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseSensitive)
 
 			Expect(val.Set("foo")).NotTo(HaveOccurred())
@@ -48,6 +50,28 @@ var _ = Describe("flag", func() {
 			Expect(val.Get()).To(Equal(fmBar))
 		})
 
+		It("returns error details", func() {
+			foomode := fmFoo
+			val := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseSensitive)
+			err := val.Set("FOOBAR")
+			var ierr *InvalidValueError
+			Expect(errors.As(err, &ierr)).To(BeTrue())
+			Expect(ierr.Type).To(Equal("mode"))
+			Expect(ierr.Input).To(Equal("FOOBAR"))
+			Expect(ierr.Allowed).To(ConsistOf("foo", "bar", "baz"))
+			Expect(ierr.Index).To(Equal(-1))
+		})
+
+		It("sets the enumeration value from an abbreviation", func() {
+			foomode := fmBaz
+			val := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithAbbreviations())
+			Expect(val.Set("f")).To(Succeed())
+			Expect(foomode).To(Equal(fmFoo))
+			Expect(val.Set("ba")).To(MatchError("'ba' is ambiguous, could be 'bar'/'Bar', 'baz'"))
+			Expect(foomode).To(Equal(fmFoo))
+		})
+
 	})
 
 	Context("slice enum flag", func() {
@@ -59,6 +83,19 @@ var _ = Describe("flag", func() {
 			Expect(val.Type()).To(Equal("modes"))
 		})
 
+		It("returns error details", func() {
+			var foomodes []FooModeTest
+			val := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+			err := val.Set("foo,bar,fool,baz")
+			var ierr *InvalidValueError
+			Expect(errors.As(err, &ierr)).To(BeTrue())
+			Expect(ierr.Type).To(Equal("modes"))
+			Expect(ierr.Input).To(Equal("fool"))
+			Expect(ierr.Index).To(Equal(2))
+			Expect(ierr.Offset).To(Equal(8))
+			Expect(foomodes).To(BeEmpty())
+		})
+
 	})
 
 	Context("retrieving the enum value", func() {
@@ -101,6 +138,62 @@ var _ = Describe("flag", func() {
 
 	})
 
+	When("passing invalid mappings or values", func() {
+
+		invalidMapping := EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: {"foo"},
+		}
+
+		It("panics on invalid mappings", func() {
+			Expect(func() {
+				f := fmFoo
+				_ = New(&f, "foo", invalidMapping, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`New requires a valid mapping: identifier 'foo' is assigned to different enum values`)))
+			Expect(func() {
+				var f FooModeTest
+				_ = NewWithoutDefault(&f, "foo", invalidMapping, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewWithoutDefault requires a valid mapping: .*`)))
+			Expect(func() {
+				var f []FooModeTest
+				_ = NewSlice(&f, "foo", invalidMapping, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewSlice requires a valid mapping: .*`)))
+			Expect(func() {
+				var f []FooModeTest
+				_ = NewSlice(&f, "foo", EnumIdentifiers[FooModeTest]{fmFoo: {"f,o,o"}}, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewSlice requires a valid mapping: identifier 'f,o,o' of enum value 1 contains separator ','`)))
+		})
+
+		It("panics on unmapped enum values", func() {
+			Expect(func() {
+				var f FooModeTest
+				_ = New(&f, "foo", FooModeIdentifiersTest, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`New requires flag to reference a mapped enum value, but 0 isn't mapped`)))
+			Expect(func() {
+				f := FooModeTest(42)
+				_ = NewWithoutDefault(&f, "foo", FooModeIdentifiersTest, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewWithoutDefault requires flag to reference a mapped enum value, but 42 isn't mapped`)))
+			Expect(func() {
+				f := []FooModeTest{fmFoo, 42}
+				_ = NewSlice(&f, "foo", FooModeIdentifiersTest, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewSlice requires flag to reference mapped enum values only, but 42 isn't mapped`)))
+		})
+
+		It("panics on mapped zero value without default", func() {
+			Expect(func() {
+				var f FooModeTest
+				_ = NewWithoutDefault(&f, "foo", EnumIdentifiers[FooModeTest]{0: {"zero"}}, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`NewWithoutDefault requires the zero enum value not to be mapped`)))
+		})
+
+		It("accepts the unmapped zero value without default", func() {
+			var f FooModeTest
+			Expect(NewWithoutDefault(&f, "foo", FooModeIdentifiersTest, EnumCaseSensitive).String()).
+				To(BeEmpty())
+		})
+
+	})
+
 	It("returns completors", func() {
 		cmd := &cobra.Command{}
 		foomodes := []FooModeTest{fmBar, fmFoo}
@@ -0,0 +1,105 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("hidden", func() {
+
+	mapping := EnumIdentifiers[FooModeTest]{
+		fmFoo: {"foo", "trace-foo"},
+		fmBar: {"bar", "Bar"},
+		fmBaz: {"trace-internals"},
+	}
+
+	It("accepts hidden identifiers and values", func() {
+		foomode := fmBar
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithHiddenIdentifiers("trace-foo"),
+			WithHiddenValues(fmBaz))
+		Expect(flag.Set("trace-internals")).To(Succeed())
+		Expect(foomode).To(Equal(fmBaz))
+		Expect(flag.String()).To(Equal("trace-internals"))
+		Expect(flag.Set("TRACE-FOO")).To(Succeed())
+		Expect(foomode).To(Equal(fmFoo))
+	})
+
+	It("doesn't abbreviate hidden identifiers", func() {
+		foomode := fmBar
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithAbbreviations(),
+			WithHiddenIdentifiers("trace-foo"))
+		Expect(flag.Set("tr")).To(Succeed())
+		Expect(foomode).To(Equal(fmBaz))
+		Expect(flag.Set("f")).To(Succeed())
+		Expect(foomode).To(Equal(fmFoo))
+
+		flag = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithAbbreviations(),
+			WithHiddenValues(fmBaz))
+		Expect(flag.Set("trace-i")).To(MatchError("must be 'bar'/'Bar', 'foo'/'trace-foo'"))
+	})
+
+	It("leaves hidden identifiers out of errors, suggestions, and help", func() {
+		foomode := fmBar
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithHiddenIdentifiers("trace-foo"),
+			WithHiddenValues(fmBaz))
+		err := flag.Set("trace-internal")
+		Expect(err).To(MatchError("must be 'bar'/'Bar', 'foo'"))
+		Expect(err.(*InvalidValueError).Allowed).To(ConsistOf("bar", "foo"))
+		Expect(flag.Allowed()).To(ConsistOf("bar", "foo"))
+	})
+
+	It("leaves hidden identifiers out of completions", func() {
+		foomode := fmBar
+		flag := New(&foomode, "mode", mapping, EnumCaseInsensitive,
+			WithHiddenIdentifiers("trace-foo"),
+			WithHiddenValues(fmBaz))
+		completions, _ := flag.value.NewCompletor(flag.names, nil)(&cobra.Command{}, nil, "")
+		Expect(completions).To(ConsistOf("foo", "bar", "Bar"))
+
+		var foomodes []FooModeTest
+		slice := NewSlice(&foomodes, "modes", mapping, EnumCaseInsensitive,
+			WithHiddenIdentifiers("trace-foo"),
+			WithHiddenValues(fmBaz))
+		completions, _ = slice.value.NewCompletor(slice.names, nil)(&cobra.Command{}, nil, "foo,")
+		Expect(completions).To(ConsistOf("foo,bar", "foo,Bar"))
+		Expect(slice.Set("foo,trace-internals")).To(Succeed())
+		Expect(foomodes).To(ConsistOf(fmFoo, fmBaz))
+	})
+
+	It("panics on invalid hidden identifiers and values", func() {
+		foomode := fmFoo
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithHiddenIdentifiers("trace"))
+		}).To(PanicWith(MatchRegexp(`hidden identifier 'trace' isn't mapped`)))
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithHiddenValues(FooModeTest(42)))
+		}).To(PanicWith(MatchRegexp(`hidden enum value 42 isn't mapped`)))
+		Expect(func() {
+			_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+				WithHiddenValues(42))
+		}).To(PanicWith(MatchRegexp(`hidden enum value 42 is of type int instead of enumflag.FooModeTest`)))
+	})
+
+})
@@ -0,0 +1,90 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("slice meta keywords", func() {
+
+	keywords := []Option{
+		WithAllKeyword("all", "all modes"),
+		WithNoneKeyword("none", "no modes"),
+		WithHiddenValues(fmBaz),
+	}
+
+	DescribeTable("expands meta keywords",
+		func(edits []string, expected []FooModeTest) {
+			foomodes := []FooModeTest{fmBar}
+			flag := newFooModesTest(&foomodes, append(keywords, WithEditOperators())...)
+			for _, edit := range edits {
+				Expect(flag.Set(edit)).To(Succeed())
+			}
+			Expect(foomodes).To(Equal(expected))
+		},
+		Entry(nil, []string{"all"}, []FooModeTest{fmFoo, fmBar}),
+		Entry(nil, []string{"ALL"}, []FooModeTest{fmFoo, fmBar}),
+		Entry(nil, []string{"none"}, []FooModeTest{}),
+		Entry(nil, []string{"all,baz"}, []FooModeTest{fmFoo, fmBar, fmBaz}),
+		Entry(nil, []string{"all,-foo"}, []FooModeTest{fmBar}),
+		Entry(nil, []string{"+all"}, []FooModeTest{fmBar, fmFoo}),
+		Entry(nil, []string{"-all"}, []FooModeTest{}),
+		Entry(nil, []string{"foo,none,baz"}, []FooModeTest{fmBaz}),
+		Entry(nil, []string{"foo", "none"}, []FooModeTest{}),
+		Entry(nil, []string{"none,+foo"}, []FooModeTest{fmFoo}),
+	)
+
+	It("reports offsets after meta keywords", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, keywords...)
+		err := flag.Set("all,none,fool")
+		Expect(err).To(HaveOccurred())
+		ierr := err.(*InvalidValueError)
+		Expect(ierr.Index).To(Equal(2))
+		Expect(ierr.Offset).To(Equal(9))
+	})
+
+	It("doesn't know meta keywords unless enabled", func() {
+		var foomodes []FooModeTest
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("all")).NotTo(Succeed())
+		Expect(flag.Set("none")).NotTo(Succeed())
+	})
+
+	It("completes meta keywords", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, keywords...)
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, _ := completor(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{
+			"foo", "bar", "Bar", "all\tall modes", "none\tno modes"}))
+		completions, _ = completor(&cobra.Command{}, nil, "ALL,")
+		Expect(completions).To(Equal([]string{
+			"ALL,foo", "ALL,bar", "ALL,Bar", "ALL,none\tno modes"}))
+	})
+
+	It("panics on clashing meta keywords", func() {
+		var foomodes []FooModeTest
+		Expect(func() {
+			_ = NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithAllKeyword("BAR", ""))
+		}).To(PanicWith(MatchRegexp(`identifier 'bar' of enum value 2 clashes with meta keyword 'BAR'`)))
+	})
+
+})
@@ -0,0 +1,139 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/pflag"
+)
+
+// LevelStep is the [github.com/spf13/pflag.Flag.NoOptDefVal] of level enum
+// flags, making each bare occurrence of a level flag, such as “-v”, step by
+// one level. LevelStep is a zero-width space, so that help doesn't show it,
+// but only the flag's type, such as “-v, --verbose level[=]”.
+const LevelStep = "\u200b"
+
+// EnumLevelFlagValue wraps a user-defined enum type value whose enum values
+// form ordered levels, as returned by [NewLevel]. Each bare occurrence of the
+// flag steps up to the next level, such as “-vvv” stepping up three levels,
+// while explicit enum values, such as “-v=debug”, set the level directly.
+type EnumLevelFlagValue[E comparable] struct {
+	EnumFlagValue[E]
+	level *enumLevel[E]
+}
+
+// enumLevel represents a scalar enumeration value that additionally can be
+// stepped through the ordered enum values.
+type enumLevel[E comparable] struct {
+	enumScalar[E]
+	step int // direction of steps: +1 up, -1 down.
+}
+
+// Set steps the level by one visible level in the level's direction if passed
+// [LevelStep], clamping at the first and last visible levels. Otherwise, Set
+// sets the level to the enum value corresponding to the passed textual
+// representation, the same as for scalar enum values.
+func (l *enumLevel[E]) Set(val string, names enumMapper[E]) error {
+	if val != LevelStep {
+		return l.enumScalar.Set(val, names)
+	}
+	// Step through all levels in order, so that we can step from a hidden
+	// level to its visible neighbor, but only ever land on visible levels.
+	levels, _ := names.Order()
+	visible := names.Visible()
+	idx := slices.Index(levels, *l.v)
+	if idx < 0 && l.step < 0 {
+		idx = len(levels)
+	}
+	for idx += l.step; idx >= 0 && idx < len(levels); idx += l.step {
+		if slices.Contains(visible, levels[idx]) {
+			*l.v = levels[idx]
+			break
+		}
+	}
+	return nil
+}
+
+// String returns the canonical name of the current level, unless decrementing
+// in which case it returns an empty string so that no default gets shown in
+// help for the companion flag.
+func (l *enumLevel[E]) String(names enumMapper[E]) string {
+	if l.step < 0 {
+		return ""
+	}
+	return l.enumScalar.String(names)
+}
+
+// NewLevel wraps a given enum variable (satisfying the predeclared type
+// identifier comparable) so that it can be used as a level flag Value, such as
+// for verbosity, stepping through the enum values in their declared order (see
+// [WithOrder] and [OrderedEnumIdentifiers]) with each bare occurrence of the
+// flag. Stepping clamps at the last level and skips hidden enum values. Use
+// [EnumLevelFlagValue.VarP] to register the level flag, as it needs its
+// [github.com/spf13/pflag.Flag.NoOptDefVal] set to [LevelStep], and
+// optionally [EnumLevelFlagValue.DecrementVarP] to register a companion flag
+// stepping down, such as “-q”.
+//
+// NewLevel panics if the mapping isn't valid (see [Validate]), the mapping
+// isn't explicitly ordered, any identifier equals [LevelStep], or if the enum
+// variable doesn't reference a mapped enum value.
+func NewLevel[E comparable](flag *E, typename string, mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) *EnumLevelFlagValue[E] {
+	if newOptions(opts).bare != nil {
+		panic("NewLevel doesn't support bare enum values, as bare level flags step instead")
+	}
+	e := new("NewLevel", flag, typename, mapping, matcher, false, opts)
+	if _, ordered := e.names.Order(); !ordered {
+		panic("NewLevel requires an explicitly ordered mapping, see WithOrder")
+	}
+	for enumval, ids := range e.names.Mapping() {
+		if slices.Contains(ids, LevelStep) {
+			panic(fmt.Sprintf("NewLevel requires identifiers other than LevelStep, but enum value %v uses it",
+				enumval))
+		}
+	}
+	level := &enumLevel[E]{enumScalar: *e.value.(*enumScalar[E]), step: +1}
+	e.value = level
+	return &EnumLevelFlagValue[E]{EnumFlagValue: *e, level: level}
+}
+
+// NoOptDefVal returns [LevelStep], the NoOptDefVal of level flags.
+func (e *EnumLevelFlagValue[E]) NoOptDefVal() string { return LevelStep }
+
+// VarP defines the level flag with the specified name, shorthand, and usage
+// in the flag set, with its NoOptDefVal set to [LevelStep]. VarP returns the
+// newly defined flag.
+func (e *EnumLevelFlagValue[E]) VarP(fs *pflag.FlagSet, name, shorthand, usage string) *pflag.Flag {
+	return varP(fs, e, name, shorthand, usage, LevelStep)
+}
+
+// DecrementVarP defines a companion flag with the specified name, shorthand,
+// and usage in the flag set, stepping the level down with each bare
+// occurrence, such as “-q”. Explicit enum values set the level directly, the
+// same as for the level flag itself. The companion flag doesn't show any
+// default in help. DecrementVarP returns the newly defined flag.
+func (e *EnumLevelFlagValue[E]) DecrementVarP(fs *pflag.FlagSet, name, shorthand, usage string) *pflag.Flag {
+	level := &enumLevel[E]{enumScalar: e.level.enumScalar, step: -1}
+	down := &EnumLevelFlagValue[E]{
+		EnumFlagValue: EnumFlagValue[E]{
+			value:    level,
+			enumtype: e.enumtype,
+			names:    e.names,
+		},
+		level: level,
+	}
+	return varP(fs, down, name, shorthand, usage, LevelStep)
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type verbosityTest int
+
+const (
+	vbWarn verbosityTest = iota
+	vbInfo
+	vbDebug
+	vbTrace
+	vbInsane
+)
+
+var verbosityIdentifiersTest = EnumIdentifiers[verbosityTest]{
+	vbWarn:   {"warn"},
+	vbInfo:   {"info"},
+	vbDebug:  {"debug"},
+	vbTrace:  {"trace"},
+	vbInsane: {"insane"},
+}
+
+var _ = Describe("level enum flags", func() {
+
+	newFlagSet := func(verbosity *verbosityTest, opts ...Option) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flag := NewLevel(verbosity, "level", verbosityIdentifiersTest, EnumCaseInsensitive,
+			append([]Option{
+				WithOrder(vbWarn, vbInfo, vbDebug, vbTrace, vbInsane),
+				WithHiddenValues(vbInsane),
+			}, opts...)...)
+		flag.VarP(fs, "verbose", "v", "increases verbosity")
+		flag.DecrementVarP(fs, "quiet", "q", "decreases verbosity")
+		return fs
+	}
+
+	DescribeTable("steps through the levels",
+		func(args []string, expected verbosityTest) {
+			verbosity := vbInfo
+			fs := newFlagSet(&verbosity)
+			Expect(fs.Parse(args)).To(Succeed())
+			Expect(verbosity).To(Equal(expected))
+		},
+		Entry(nil, []string{}, vbInfo),
+		Entry(nil, []string{"-v"}, vbDebug),
+		Entry(nil, []string{"-vv"}, vbTrace),
+		Entry(nil, []string{"-vvvvv"}, vbTrace),
+		Entry(nil, []string{"--verbose", "--verbose"}, vbTrace),
+		Entry(nil, []string{"-q"}, vbWarn),
+		Entry(nil, []string{"-qqq"}, vbWarn),
+		Entry(nil, []string{"-vv", "-q"}, vbDebug),
+		Entry(nil, []string{"-v=warn", "-v"}, vbInfo),
+		Entry(nil, []string{"--verbose=TRACE"}, vbTrace),
+		Entry(nil, []string{"--verbose=insane", "-q"}, vbTrace),
+		Entry(nil, []string{"-q=debug"}, vbDebug),
+	)
+
+	It("rejects invalid explicit levels", func() {
+		verbosity := vbInfo
+		fs := newFlagSet(&verbosity)
+		Expect(fs.Parse([]string{"-v=debgu"})).To(MatchError(ContainSubstring("did you mean 'debug'?")))
+		Expect(fs.Parse([]string{"-v=+1"})).To(MatchError(ContainSubstring("must be 'warn', 'info'")))
+		Expect(verbosity).To(Equal(vbInfo))
+
+		flag := NewLevel(&verbosity, "level", verbosityIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(vbWarn, vbInfo))
+		Expect(flag.Type()).To(Equal("level"))
+		err := flag.Set("+1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*InvalidValueError).Type).To(Equal("level"))
+	})
+
+	It("shows the canonical name of the default level in help", func() {
+		verbosity := vbInfo
+		fs := newFlagSet(&verbosity)
+		Expect(fs.Lookup("verbose").DefValue).To(Equal("info"))
+		Expect(fs.Lookup("quiet").DefValue).To(BeEmpty())
+		Expect(fs.FlagUsages()).To(MatchRegexp(`-v, --verbose level\[=\S*\]\s+increases verbosity \(default info\)\n`))
+		Expect(fs.FlagUsages()).To(MatchRegexp(`-q, --quiet level\[=\S*\]\s+decreases verbosity\n`))
+		Expect(fs.FlagUsages()).NotTo(ContainSubstring("+1"))
+	})
+
+	It("panics on unordered mappings", func() {
+		verbosity := vbInfo
+		Expect(func() { NewLevel(&verbosity, "level", verbosityIdentifiersTest, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewLevel requires an explicitly ordered mapping`)))
+	})
+
+	It("panics on identifiers clashing with the level step", func() {
+		verbosity := vbInfo
+		Expect(func() {
+			NewLevel(&verbosity, "level", EnumIdentifiers[verbosityTest]{vbInfo: {"info", LevelStep}}, EnumCaseInsensitive,
+				WithOrder(vbInfo))
+		}).To(PanicWith(MatchRegexp(`NewLevel requires identifiers other than LevelStep, but enum value 1 uses it`)))
+		ids := OrderedEnumIdentifiers[verbosityTest]{{vbWarn, []string{"warn"}}, {vbInfo, []string{"info", LevelStep}}}
+		Expect(func() {
+			NewLevel(&verbosity, "level", ids.Mapping(), EnumCaseInsensitive, ids.Order())
+		}).To(PanicWith(MatchRegexp(`NewLevel requires identifiers other than LevelStep, but enum value 1 uses it`)))
+	})
+
+})
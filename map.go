@@ -0,0 +1,375 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// EnumMapFlagValue wraps a map with enum keys, enum values, or both, as
+// returned by [NewMap], [NewKeyMap], and [NewValueMap]. It implements the
+// [github.com/spf13/pflag.Value] interface, accepting comma-separated
+// “key=value” elements, such as “--limits=cpu=2,mem=4g”.
+type EnumMapFlagValue[K, V comparable] struct {
+	v        *map[K]V
+	def      map[K]V       // default map for resetting.
+	merge    bool          // replace the complete map or merge entries?
+	list     listSeparator // separator and quoting of elements.
+	keys     mapPart[K]
+	values   mapPart[V]
+	enumtype string // user-friendly name of the map type.
+}
+
+// mapPart parses and names either the keys or the values of maps, which are
+// either enum values or free-form strings.
+type mapPart[T comparable] struct {
+	names *enumMapper[T] // nil for free-form strings.
+}
+
+// WithKeyOptions passes the specified options only to the enum keys of map
+// enum flags, such as [WithOrder] for the key enum type.
+func WithKeyOptions(opts ...Option) Option {
+	return func(o *options) {
+		o.keyOpts = append(o.keyOpts, opts...)
+	}
+}
+
+// WithValueOptions passes the specified options only to the enum values of
+// map enum flags, such as [WithOrder] for the value enum type.
+func WithValueOptions(opts ...Option) Option {
+	return func(o *options) {
+		o.valueOpts = append(o.valueOpts, opts...)
+	}
+}
+
+// NewMap wraps a given map variable with enum keys as well as enum values so
+// that it can be used as a flag Value with [github.com/spf13/pflag.Var] and
+// [github.com/spf13/pflag.VarP]. Users specify comma-separated “key=value”
+// elements, such as “--log-level=http=debug,db=warn”. The first Set replaces
+// any default map entries, while subsequent Sets merge their entries, with
+// later entries for the same key winning.
+//
+// The matcher and options apply to both keys and values; use
+// [WithKeyOptions] and [WithValueOptions] for options specific to either.
+//
+// NewMap panics if either mapping isn't valid (see [Validate]), the matcher is
+// nil, any identifier contains “=” or the separator (see [WithSeparator] and
+// [WithQuoting]), or if the map variable references unmapped keys or values.
+func NewMap[K, V comparable](flag *map[K]V, typename string, keys EnumIdentifiers[K], values EnumIdentifiers[V], matcher Matcher, opts ...Option) *EnumMapFlagValue[K, V] {
+	if keys == nil || values == nil {
+		panic("NewMap requires mappings not to be nil")
+	}
+	return newMap("NewMap", flag, typename, keys, values, matcher, opts)
+}
+
+// NewKeyMap wraps a given map variable with enum keys and free-form string
+// values, such as “--limits=cpu=2,mem=4g”, so that it can be used as a flag
+// Value with [github.com/spf13/pflag.Var] and [github.com/spf13/pflag.VarP].
+// See [NewMap] for details.
+func NewKeyMap[K comparable](flag *map[K]string, typename string, keys EnumIdentifiers[K], matcher Matcher, opts ...Option) *EnumMapFlagValue[K, string] {
+	if keys == nil {
+		panic("NewKeyMap requires mapping not to be nil")
+	}
+	return newMap[K, string]("NewKeyMap", flag, typename, keys, nil, matcher, opts)
+}
+
+// NewValueMap wraps a given map variable with free-form string keys and enum
+// values, such as “--log-level=http=debug,db=warn”, so that it can be used as
+// a flag Value with [github.com/spf13/pflag.Var] and
+// [github.com/spf13/pflag.VarP]. See [NewMap] for details.
+func NewValueMap[V comparable](flag *map[string]V, typename string, values EnumIdentifiers[V], matcher Matcher, opts ...Option) *EnumMapFlagValue[string, V] {
+	if values == nil {
+		panic("NewValueMap requires mapping not to be nil")
+	}
+	return newMap[string, V]("NewValueMap", flag, typename, nil, values, matcher, opts)
+}
+
+// newMap returns a new map enum flag value, with nil key or value mappings
+// denoting free-form strings.
+func newMap[K, V comparable](ctor string, flag *map[K]V, typename string, keys EnumIdentifiers[K], values EnumIdentifiers[V], matcher Matcher, opts []Option) *EnumMapFlagValue[K, V] {
+	if flag == nil {
+		panic(fmt.Sprintf("%s requires flag to be a non-nil pointer to a map", ctor))
+	}
+	o := newOptions(opts)
+	keyPart, err := newMapPart(keys, matcher, o, append(slices.Clone(opts), o.keyOpts...))
+	if err != nil {
+		panic(fmt.Sprintf("%s requires a valid key mapping: %s", ctor, err))
+	}
+	valuePart, err := newMapPart(values, matcher, o, append(slices.Clone(opts), o.valueOpts...))
+	if err != nil {
+		panic(fmt.Sprintf("%s requires a valid value mapping: %s", ctor, err))
+	}
+	for key, value := range *flag {
+		if !keyPart.mapped(key) {
+			panic(fmt.Sprintf("%s requires flag to reference mapped keys only, but %v isn't mapped",
+				ctor, key))
+		}
+		if !valuePart.mapped(value) {
+			panic(fmt.Sprintf("%s requires flag to reference mapped values only, but %v isn't mapped",
+				ctor, value))
+		}
+	}
+	return &EnumMapFlagValue[K, V]{
+		v:        flag,
+		def:      maps.Clone(*flag),
+		list:     listSeparator{sep: o.separator, quoting: o.quoting},
+		keys:     keyPart,
+		values:   valuePart,
+		enumtype: typename,
+	}
+}
+
+// newMapPart returns a new mapPart for the specified mapping, or for free-form
+// strings if the mapping is nil, after validating the mapping.
+func newMapPart[T comparable](mapping EnumIdentifiers[T], matcher Matcher, o options, opts []Option) (mapPart[T], error) {
+	if mapping == nil {
+		return mapPart[T]{}, nil
+	}
+	if err := errors.Join(
+		validate(mapping, matcher, newOptions(opts)),
+		validateListSyntax(mapping, o),
+		validateSeparator(mapping, "="),
+	); err != nil {
+		return mapPart[T]{}, err
+	}
+	names := newEnumMapper(mapping, matcher, opts...)
+	return mapPart[T]{names: &names}, nil
+}
+
+// mapped returns true if the specified key or value is either a free-form
+// string or a mapped enum value.
+func (p mapPart[T]) mapped(t T) bool {
+	return p.names == nil || len(p.names.Lookup(t)) > 0
+}
+
+// parse returns the key or value corresponding to the specified textual
+// representation, as well as a warning in case of deprecations. Free-form
+// strings are returned unchanged.
+func (p mapPart[T]) parse(s string) (T, string, error) {
+	if p.names == nil {
+		return any(s).(T), "", nil
+	}
+	return p.names.Parse(s)
+}
+
+// warn emits the specified non-empty warnings.
+func (p mapPart[T]) warn(warnings ...string) {
+	if p.names != nil {
+		p.names.Warn(warnings...)
+	}
+}
+
+// name returns the textual representation of the specified key or value,
+// which is the canonical name in case of enum values.
+func (p mapPart[T]) name(t T) string {
+	if p.names == nil {
+		return any(t).(string)
+	}
+	if ids := p.names.Lookup(t); len(ids) > 0 {
+		return ids[0]
+	}
+	return unknown
+}
+
+// sorted returns the specified keys in mapping order in case of enum values,
+// and otherwise sorted alphabetically.
+func (p mapPart[T]) sorted(keys []T) []T {
+	if p.names == nil {
+		return slices.SortedFunc(slices.Values(keys), func(a, b T) int {
+			return strings.Compare(any(a).(string), any(b).(string))
+		})
+	}
+	order, _ := p.names.Order()
+	rank := make(map[T]int, len(order))
+	for pos, enumval := range order {
+		rank[enumval] = pos
+	}
+	return slices.SortedFunc(slices.Values(keys), func(a, b T) int {
+		ra, oka := rank[a]
+		rb, okb := rank[b]
+		if !oka || !okb {
+			// unmapped keys go last.
+			return cmp.Compare(p.name(a), p.name(b))
+		}
+		return ra - rb
+	})
+}
+
+// completions returns the completions for all advertised identifiers in
+// order, each formatted with the specified function and with optional help
+// texts, as well as whether the order has been explicitly declared. There are
+// no completions for free-form strings.
+func (p mapPart[T]) completions(help Help[T], skip func(id string) bool, format func(id string) string) ([]string, bool) {
+	if p.names == nil {
+		return nil, false
+	}
+	completions := []string{}
+	order, ordered := p.names.Order()
+	for _, enumval := range order {
+		helptext := p.names.helptext(help, enumval)
+		for _, id := range p.names.Listed()[enumval] {
+			if skip(id) {
+				continue
+			}
+			completions = append(completions, format(id)+helptext)
+		}
+	}
+	return completions, ordered
+}
+
+// Set sets or merges the map entries corresponding to the passed textual
+// representation of “key=value” elements. If any element isn't of the form
+// “key=value”, or any enum key or value doesn't match, an
+// [*InvalidValueError] is returned instead and the map isn't changed. In case
+// of deprecated identifiers or enum values warnings get emitted. The first
+// call to Set will always clear any previous default map entries. All
+// subsequent calls to Set will merge the specified entries with the current
+// map entries.
+func (e *EnumMapFlagValue[K, V]) Set(val string) error {
+	elements, err := e.list.split(val)
+	if err != nil {
+		return e.typed(err)
+	}
+	updates := make(map[K]V, len(elements))
+	var keyWarnings, valueWarnings []string
+	for idx, element := range elements {
+		keytext, valuetext, ok := strings.Cut(element.text, "=")
+		if !ok || (e.keys.names == nil && keytext == "") {
+			return e.typed(&InvalidValueError{
+				Input:  element.text,
+				Index:  idx,
+				Offset: element.offset,
+				reason: fmt.Sprintf("'%s' must be of the form key=value", element.text),
+			})
+		}
+		key, warning, err := e.keys.parse(keytext)
+		if err != nil {
+			return e.located(err, idx, element.offset)
+		}
+		keyWarnings = append(keyWarnings, warning)
+		value, warning, err := e.values.parse(valuetext)
+		if err != nil {
+			return e.located(err, idx, element.offset+len(keytext)+1)
+		}
+		valueWarnings = append(valueWarnings, warning)
+		updates[key] = value
+	}
+	result := make(map[K]V, len(*e.v)+len(updates))
+	if e.merge {
+		maps.Copy(result, *e.v)
+	}
+	maps.Copy(result, updates)
+	*e.v = result
+	e.merge = true // ...and next time: merge.
+	e.keys.warn(keyWarnings...)
+	e.values.warn(valueWarnings...)
+	return nil
+}
+
+// located returns the specified error with the map type, index, and offset
+// filled in, if it is an [*InvalidValueError].
+func (e *EnumMapFlagValue[K, V]) located(err error, index int, offset int) error {
+	var ierr *InvalidValueError
+	if errors.As(err, &ierr) {
+		ierr.Index = index
+		ierr.Offset = offset
+	}
+	return e.typed(err)
+}
+
+// typed returns the specified error with the map type filled in, if it is an
+// [*InvalidValueError].
+func (e *EnumMapFlagValue[K, V]) typed(err error) error {
+	var ierr *InvalidValueError
+	if errors.As(err, &ierr) {
+		ierr.Type = e.enumtype
+	}
+	return err
+}
+
+// String returns the textual representation of the map, such as
+// “[cpu=2,mem=4g]”, with the entries in mapping order of the keys in case of
+// enum keys, and otherwise sorted by keys. Enum keys and values are
+// represented by their canonical names.
+func (e *EnumMapFlagValue[K, V]) String() string {
+	keys := e.keys.sorted(slices.Collect(maps.Keys(*e.v)))
+	elements := make([]string, 0, len(keys))
+	for _, key := range keys {
+		elements = append(elements, e.keys.name(key)+"="+e.values.name((*e.v)[key]))
+	}
+	return "[" + e.list.join(elements) + "]"
+}
+
+// Type returns the name of the flag value type. The type name is used in error
+// messages.
+func (e *EnumMapFlagValue[K, V]) Type() string { return e.enumtype }
+
+// Get returns the current map for convenience.
+func (e *EnumMapFlagValue[K, V]) Get() any { return *e.v }
+
+// GetMapValue returns the current map.
+func (e *EnumMapFlagValue[K, V]) GetMapValue() map[K]V { return *e.v }
+
+// Reset restores the map to its default at the time the map enum flag value
+// was created, and forgets about any previous Set, so that the next Set again
+// is treated as the first one.
+func (e *EnumMapFlagValue[K, V]) Reset() {
+	*e.v = maps.Clone(e.def)
+	e.merge = false
+}
+
+// RegisterCompletion registers completions for the specified (flag) name, with
+// optional help texts for keys and values. Before “=”, enum keys not yet
+// present in the map being completed are completed, and after “=”, enum
+// values are completed. There are no completions for free-form keys and
+// values.
+func (e *EnumMapFlagValue[K, V]) RegisterCompletion(cmd *cobra.Command, name string, keyHelp Help[K], valueHelp Help[V]) error {
+	return cmd.RegisterFlagCompletionFunc(name, e.NewCompletor(keyHelp, valueHelp))
+}
+
+// NewCompletor returns a cobra Completor that completes map enum flag values.
+func (e *EnumMapFlagValue[K, V]) NewCompletor(keyHelp Help[K], valueHelp Help[V]) Completor {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix, completes := e.list.tail(toComplete)
+		directive := cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		var completions []string
+		var ordered bool
+		if key, _, ok := strings.Cut(toComplete[len(prefix):], "="); ok {
+			completions, ordered = e.values.completions(valueHelp,
+				func(string) bool { return false },
+				func(id string) string { return prefix + key + "=" + id })
+		} else {
+			completions, ordered = e.keys.completions(keyHelp,
+				func(id string) bool {
+					return slices.ContainsFunc(completes, func(complete string) bool {
+						key, _, _ := strings.Cut(complete, "=")
+						return e.keys.names.Matches(key, id)
+					})
+				},
+				func(id string) string { return prefix + id + "=" })
+		}
+		if ordered {
+			directive |= cobra.ShellCompDirectiveKeepOrder
+		}
+		return completions, directive
+	}
+}
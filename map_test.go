@@ -0,0 +1,200 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type resourceTest int
+
+const (
+	resCPU resourceTest = iota + 1
+	resMem
+	resIO
+)
+
+var resourceIdentifiersTest = EnumIdentifiers[resourceTest]{
+	resCPU: {"cpu"},
+	resMem: {"mem", "memory"},
+	resIO:  {"io"},
+}
+
+var _ = Describe("map enum flags", func() {
+
+	Context("enum keys", func() {
+
+		It("sets and merges", func() {
+			limits := map[resourceTest]string{resIO: "10"}
+			flag := NewKeyMap(&limits, "limits", resourceIdentifiersTest, EnumCaseInsensitive)
+			Expect(flag.String()).To(Equal("[io=10]"))
+			Expect(flag.Set("CPU=2,memory=4g")).To(Succeed())
+			Expect(limits).To(Equal(map[resourceTest]string{resCPU: "2", resMem: "4g"}))
+			Expect(flag.Set("cpu=4,io=")).To(Succeed())
+			Expect(flag.GetMapValue()).To(Equal(map[resourceTest]string{resCPU: "4", resMem: "4g", resIO: ""}))
+			Expect(flag.String()).To(Equal("[cpu=4,io=,mem=4g]"))
+		})
+
+		It("reports invalid keys", func() {
+			limits := map[resourceTest]string{}
+			flag := NewKeyMap(&limits, "limits", resourceIdentifiersTest, EnumCaseInsensitive)
+			err := flag.Set("cpu=2,mme=4g")
+			Expect(err).To(MatchError(ContainSubstring("did you mean 'mem'?")))
+			var ierr *InvalidValueError
+			Expect(errors.As(err, &ierr)).To(BeTrue())
+			Expect(ierr.Type).To(Equal("limits"))
+			Expect(ierr.Input).To(Equal("mme"))
+			Expect(ierr.Index).To(Equal(1))
+			Expect(ierr.Offset).To(Equal(6))
+			Expect(limits).To(BeEmpty())
+		})
+
+		It("rejects elements without values", func() {
+			limits := map[resourceTest]string{}
+			flag := NewKeyMap(&limits, "limits", resourceIdentifiersTest, EnumCaseInsensitive)
+			err := flag.Set("cpu=2,mem")
+			Expect(err).To(MatchError("'mem' must be of the form key=value"))
+			Expect(err.(*InvalidValueError).Offset).To(Equal(6))
+		})
+
+		It("completes keys not yet present", func() {
+			limits := map[resourceTest]string{}
+			flag := NewKeyMap(&limits, "limits", resourceIdentifiersTest, EnumCaseInsensitive,
+				WithKeyOptions(WithOrder(resCPU, resMem, resIO)))
+			completor := flag.NewCompletor(Help[resourceTest]{resCPU: "cores"}, nil)
+			completions, directive := completor(&cobra.Command{}, nil, "")
+			Expect(completions).To(Equal([]string{"cpu=\tcores", "mem=", "memory=", "io="}))
+			Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp |
+				cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveKeepOrder))
+			completions, _ = completor(&cobra.Command{}, nil, "MEM=4g,")
+			Expect(completions).To(Equal([]string{"MEM=4g,cpu=\tcores", "MEM=4g,memory=", "MEM=4g,io="}))
+			completions, _ = completor(&cobra.Command{}, nil, "cpu=")
+			Expect(completions).To(BeEmpty())
+		})
+
+	})
+
+	Context("enum values", func() {
+
+		It("sets and merges", func() {
+			levels := map[string]levelTest{}
+			flag := NewValueMap(&levels, "levels", levelIdentifiersTest, EnumCaseInsensitive)
+			Expect(flag.Set("http=DEBUG,db=warn")).To(Succeed())
+			Expect(flag.Set("db=error")).To(Succeed())
+			Expect(levels).To(Equal(map[string]levelTest{"http": lvlDebug, "db": lvlError}))
+			Expect(flag.String()).To(Equal("[db=error,http=debug]"))
+		})
+
+		It("reports invalid values", func() {
+			levels := map[string]levelTest{}
+			flag := NewValueMap(&levels, "levels", levelIdentifiersTest, EnumCaseInsensitive)
+			err := flag.Set("http=debug,db=wran")
+			Expect(err).To(HaveOccurred())
+			ierr := err.(*InvalidValueError)
+			Expect(ierr.Input).To(Equal("wran"))
+			Expect(ierr.Index).To(Equal(1))
+			Expect(ierr.Offset).To(Equal(14))
+			Expect(flag.Set("=debug")).To(MatchError("'=debug' must be of the form key=value"))
+		})
+
+		It("completes values after '='", func() {
+			levels := map[string]levelTest{}
+			flag := NewValueMap(&levels, "levels", levelIdentifiersTest, EnumCaseInsensitive,
+				WithHiddenIdentifiers("8"),
+				WithValueOptions(WithOrder(lvlDebug, lvlInfo, lvlWarn, lvlError, lvlFatal)))
+			completor := flag.NewCompletor(nil, Help[levelTest]{lvlInfo: "informational"})
+			completions, _ := completor(&cobra.Command{}, nil, "db=warn,http=")
+			Expect(completions).To(Equal([]string{
+				"db=warn,http=debug", "db=warn,http=info\tinformational", "db=warn,http=warn",
+				"db=warn,http=error", "db=warn,http=fatal"}))
+			completions, _ = completor(&cobra.Command{}, nil, "db=warn,")
+			Expect(completions).To(BeEmpty())
+		})
+
+	})
+
+	Context("enum keys and values", func() {
+
+		It("sets, resets, and registers with pflag", func() {
+			levels := map[resourceTest]levelTest{resIO: lvlInfo}
+			flag := NewMap(&levels, "levels", resourceIdentifiersTest, levelIdentifiersTest, EnumCaseInsensitive)
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			fs.Var(flag, "levels", "")
+			Expect(fs.Lookup("levels").DefValue).To(Equal("[io=info]"))
+			Expect(fs.Parse([]string{"--levels=cpu=debug", "--levels=mem=fatal"})).To(Succeed())
+			Expect(levels).To(Equal(map[resourceTest]levelTest{resCPU: lvlDebug, resMem: lvlFatal}))
+			Expect(flag.Type()).To(Equal("levels"))
+			Expect(flag.Get()).To(Equal(levels))
+
+			flag.Reset()
+			Expect(levels).To(Equal(map[resourceTest]levelTest{resIO: lvlInfo}))
+		})
+
+		It("uses the separator and quoting", func() {
+			levels := map[string]levelTest{}
+			flag := NewValueMap(&levels, "levels", levelIdentifiersTest, EnumCaseInsensitive,
+				WithSeparator(";"), WithQuoting())
+			Expect(flag.Set(`"a;b=debug";c=info`)).To(Succeed())
+			Expect(levels).To(Equal(map[string]levelTest{"a;b": lvlDebug, "c": lvlInfo}))
+			Expect(flag.String()).To(Equal(`["a;b=debug";c=info]`))
+		})
+
+	})
+
+	DescribeTable("panics on invalid parameters",
+		func(f func(), expected string) {
+			Expect(f).To(PanicWith(MatchRegexp(expected)))
+		},
+		Entry(nil, func() {
+			NewMap[resourceTest, levelTest](nil, "m", resourceIdentifiersTest, levelIdentifiersTest, EnumCaseInsensitive)
+		}, `NewMap requires flag to be a non-nil pointer to a map`),
+		Entry(nil, func() {
+			m := map[resourceTest]levelTest{}
+			NewMap(&m, "m", nil, levelIdentifiersTest, EnumCaseInsensitive)
+		}, `NewMap requires mappings not to be nil`),
+		Entry(nil, func() {
+			m := map[resourceTest]string{}
+			NewKeyMap(&m, "m", nil, EnumCaseInsensitive)
+		}, `NewKeyMap requires mapping not to be nil`),
+		Entry(nil, func() {
+			m := map[string]levelTest{}
+			NewValueMap(&m, "m", nil, EnumCaseInsensitive)
+		}, `NewValueMap requires mapping not to be nil`),
+		Entry(nil, func() {
+			m := map[resourceTest]string{}
+			NewKeyMap(&m, "m", EnumIdentifiers[resourceTest]{resCPU: {"a=b"}}, EnumCaseInsensitive)
+		}, `NewKeyMap requires a valid key mapping: identifier 'a=b' of enum value 1 contains separator '='`),
+		Entry(nil, func() {
+			m := map[resourceTest]levelTest{}
+			NewMap(&m, "m", resourceIdentifiersTest, levelIdentifiersTest, EnumCaseInsensitive,
+				WithKeyOptions(WithOrder(lvlInfo)))
+		}, `NewMap requires a valid key mapping: .* is of type`),
+		Entry(nil, func() {
+			m := map[resourceTest]string{42: "foo"}
+			NewKeyMap(&m, "m", resourceIdentifiersTest, EnumCaseInsensitive)
+		}, `NewKeyMap requires flag to reference mapped keys only, but 42 isn't mapped`),
+		Entry(nil, func() {
+			m := map[string]levelTest{"foo": 42}
+			NewValueMap(&m, "m", levelIdentifiersTest, EnumCaseInsensitive)
+		}, `NewValueMap requires flag to reference mapped values only, but 42 isn't mapped`),
+	)
+
+})
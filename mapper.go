@@ -17,7 +17,6 @@ package enumflag
 import (
 	"fmt"
 	"slices"
-	"sort"
 	"strings"
 )
 
@@ -27,22 +26,160 @@ import (
 // representation (identifier). If more than one textual representation exists
 // for the same enumeration value, then the first textual representation is
 // considered to be the canonical one.
+//
+// Enum flag values take a snapshot of the mapping when they're created, so
+// later changes to a mapping don't affect existing enum flag values.
 type EnumIdentifiers[E comparable] map[E][]string
 
-// enumMapper is an optionally case insensitive map from enum values to their
-// corresponding textual representations.
+// enumMapper is a map from enum values to their corresponding textual
+// representations, and vice versa, using a Matcher to match user input to
+// identifiers.
+//
+// An enumMapper works on a snapshot of the enum identifiers mapping taken at
+// creation time. Later modifications to the originally passed mapping thus
+// don't affect an enumMapper. All lookup structures are precomputed when
+// creating an enumMapper and never modified afterwards, so an enumMapper can be
+// used concurrently.
 type enumMapper[E comparable] struct {
-	m           EnumIdentifiers[E]
-	sensitivity EnumCaseSensitivity
+	m             EnumIdentifiers[E] // snapshot of the mapping
+	abbreviations bool               // accept unique identifier prefixes?
+	codes         map[string]E       // numeric codes in decimal form, if enabled.
+
+	listed           EnumIdentifiers[E]     // advertised identifiers only.
+	order            []E                    // all mapped enum values in order.
+	ordered          bool                   // explicitly ordered mapping?
+	deprecatedIds    map[string]deprecation // deprecated identifiers.
+	deprecatedValues map[E]deprecation      // deprecated enum values.
+	hiddenIds        map[string]struct{}    // hidden identifiers.
+	hiddenValues     map[E]struct{}         // hidden enum values.
+	warn             func(warning string)   // warning sink.
+	bare             *E                     // bare enum value, if any.
+
+	indices []matcherIndex[E] // per-identifier Matcher indices first, default last.
+	allowed []string          // canonical names, sorted
+	reason  string            // pre-rendered "must be ..." error message
+}
+
+// matcherIndex indexes the identifiers matched by a particular Matcher by
+// their normalized forms.
+type matcherIndex[E comparable] struct {
+	matcher Matcher
+	index   map[string][]indexKey[E] // normalized identifier to identifiers
+	keys    []indexKey[E]            // sorted by normalized identifiers for prefix matching.
 }
 
-// newEnumMapper returns a new enumMapper for the given mapping and case
-// sensitivity or insensitivity.
-func newEnumMapper[E comparable](mapping EnumIdentifiers[E], sensitivity EnumCaseSensitivity) enumMapper[E] {
-	return enumMapper[E]{
-		m:           mapping,
-		sensitivity: sensitivity,
+// indexKey is a normalized identifier, together with its original identifier
+// and the enum value it maps to.
+type indexKey[E comparable] struct {
+	key     string
+	id      string
+	pos     int // position of id in the identifiers of enumval
+	enumval E
+	listed  bool // advertised in completions, error messages, and suggestions?
+	hidden  bool // never matched as abbreviation?
+}
+
+// newEnumMapper returns a new enumMapper for the given mapping and Matcher,
+// such as an EnumCaseSensitivity, as well as further optional settings.
+func newEnumMapper[E comparable](mapping EnumIdentifiers[E], matcher Matcher, opts ...Option) enumMapper[E] {
+	o := newOptions(opts)
+	m := enumMapper[E]{
+		m:             make(EnumIdentifiers[E], len(mapping)),
+		abbreviations: o.abbreviations,
+		indices:       make([]matcherIndex[E], len(o.overrides)+1),
+		listed:        make(EnumIdentifiers[E], len(mapping)),
+		deprecatedIds: o.deprecatedIds,
+		warn:          o.warn,
+		hiddenIds:     make(map[string]struct{}, len(o.hiddenIds)),
+		hiddenValues:  make(map[E]struct{}, len(o.hiddenValues)),
+	}
+	for _, id := range o.hiddenIds {
+		m.hiddenIds[id] = struct{}{}
+	}
+	for _, enumval := range o.hiddenValues {
+		if enumval, ok := enumval.(E); ok {
+			m.hiddenValues[enumval] = struct{}{}
+		}
+	}
+	if m.warn == nil {
+		m.warn = warnStderr
 	}
+	if bare, ok := o.bare.(E); ok {
+		m.bare = &bare
+	}
+	if len(o.deprecatedValues) > 0 {
+		m.deprecatedValues = make(map[E]deprecation, len(o.deprecatedValues))
+		for enumval, d := range o.deprecatedValues {
+			if enumval, ok := enumval.(E); ok {
+				m.deprecatedValues[enumval] = d
+			}
+		}
+	}
+	for idx, override := range o.overrides {
+		m.indices[idx].matcher = override.matcher
+	}
+	m.indices[len(o.overrides)].matcher = matcher
+	if o.numericCodes {
+		m.codes = map[string]E{}
+	}
+	matcherOf := o.matcherIndices()
+	for enumval, ids := range mapping {
+		ids = slices.Clone(ids)
+		m.m[enumval] = ids
+		if len(ids) == 0 {
+			continue
+		}
+		listed := slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+			return !m.isListed(enumval, id)
+		})
+		if len(listed) > 0 {
+			m.listed[enumval] = listed
+		}
+		if m.codes != nil {
+			if code, ok := codeOf(enumval); ok {
+				m.codes[code] = enumval
+			}
+		}
+		for pos, id := range ids {
+			mi := &m.indices[len(o.overrides)]
+			if idx, ok := matcherOf[id]; ok {
+				mi = &m.indices[idx]
+			}
+			mi.keys = append(mi.keys, indexKey[E]{
+				key:     mi.matcher.Normalize(id),
+				id:      id,
+				pos:     pos,
+				enumval: enumval,
+				listed:  m.isListed(enumval, id),
+				hidden:  m.isHidden(enumval, id),
+			})
+		}
+	}
+	for idx := range m.indices {
+		mi := &m.indices[idx]
+		slices.SortFunc(mi.keys, func(a, b indexKey[E]) int {
+			return strings.Compare(a.key, b.key)
+		})
+		mi.index = make(map[string][]indexKey[E], len(mi.keys))
+		for _, k := range mi.keys {
+			mi.index[k.key] = append(mi.index[k.key], k)
+		}
+	}
+	m.order, m.ordered = orderOf(m.m, m.listed, o.order)
+	// The error message lists all valid and advertised textual
+	// representations in order, so the error message is stable.
+	allids := make([][]string, 0, len(m.listed))
+	for _, enumval := range m.order {
+		if ids, ok := m.listed[enumval]; ok {
+			allids = append(allids, ids)
+		}
+	}
+	m.allowed = make([]string, 0, len(allids))
+	for _, ids := range allids {
+		m.allowed = append(m.allowed, ids[0])
+	}
+	m.reason = "must be " + quotedAll(allids)
+	return m
 }
 
 // Lookup returns the enum textual representations (identifiers) for the
@@ -51,39 +188,274 @@ func (m enumMapper[E]) Lookup(enum E) (names []string) {
 	return m.m[enum]
 }
 
+// isListed returns true if the specified identifier of an enum value is to be
+// advertised in completions, error messages, help, and suggestions; that is,
+// it is neither hidden nor deprecated.
+func (m enumMapper[E]) isListed(enumval E, id string) bool {
+	if m.isHidden(enumval, id) {
+		return false
+	}
+	if _, ok := m.deprecatedValues[enumval]; ok {
+		return false
+	}
+	if _, ok := m.deprecatedIds[id]; ok {
+		return false
+	}
+	return true
+}
+
+// isHidden returns true if the specified identifier of an enum value is
+// hidden.
+func (m enumMapper[E]) isHidden(enumval E, id string) bool {
+	if _, ok := m.hiddenValues[enumval]; ok {
+		return true
+	}
+	_, ok := m.hiddenIds[id]
+	return ok
+}
+
+// Parse returns the enumeration value corresponding with the specified textual
+// representation as ValueOf does, and additionally a warning if the textual
+// representation refers to a deprecated identifier or enum value; otherwise,
+// the warning is empty.
+func (m enumMapper[E]) Parse(name string) (E, string, error) {
+	enumval, id, err := m.resolve(name)
+	if err != nil {
+		return enumval, "", err
+	}
+	if d, ok := m.deprecatedIds[id]; ok {
+		return enumval, d.warning(name), nil
+	}
+	if d, ok := m.deprecatedValues[enumval]; ok {
+		return enumval, d.warning(name), nil
+	}
+	return enumval, "", nil
+}
+
+// Warn emits the specified non-empty warnings.
+func (m enumMapper[E]) Warn(warnings ...string) {
+	for _, warning := range warnings {
+		if warning != "" {
+			m.warn(warning)
+		}
+	}
+}
+
 // ValueOf returns the enumeration value corresponding with the specified
-// textual representation (identifier), or an error if no match is found.
+// textual representation (identifier), or an [*InvalidValueError] if no match
+// is found. If numeric codes are enabled, the numeric code of a mapped enum
+// value is accepted as well, if no identifier matches exactly. If
+// abbreviations are enabled, a unique prefix of an identifier is accepted
+// last.
 func (m enumMapper[E]) ValueOf(name string) (E, error) {
-	comparefn := func(s string) bool { return s == name }
-	if m.sensitivity == EnumCaseInsensitive {
-		name = strings.ToLower(name)
-		comparefn = func(s string) bool { return strings.ToLower(s) == name }
+	enumval, _, err := m.resolve(name)
+	return enumval, err
+}
+
+// resolve returns the enumeration value corresponding with the specified
+// textual representation, together with the matching identifier. In case of
+// numeric codes, the identifier is empty.
+func (m enumMapper[E]) resolve(name string) (E, string, error) {
+	for _, mi := range m.indices {
+		for _, k := range mi.index[mi.matcher.Normalize(name)] {
+			if mi.matcher.Match(name, k.id) {
+				return k.enumval, k.id, nil
+			}
+		}
+	}
+	var zero E
+	if m.codes != nil {
+		if code, ok := parseCode(name); ok {
+			if enumval, ok := m.codes[code]; ok {
+				return enumval, "", nil
+			}
+			// There's no point in suggesting identifiers for numbers.
+			return zero, "", m.invalid(name)
+		}
 	}
-	// Try to find a matching enum value textual representation, and then take
-	// its enumeration value ("code").
-	for enumval, ids := range m.m {
-		if slices.IndexFunc(ids, comparefn) >= 0 {
-			return enumval, nil
+	if m.abbreviations && name != "" {
+		enumval, matches := m.prefixed(name)
+		if len(matches) == 1 {
+			// Prefer an advertised identifier, if the prefix matches any.
+			id := matches[0][0]
+			if idx := slices.IndexFunc(matches[0], func(id string) bool {
+				return m.isListed(enumval, id)
+			}); idx >= 0 {
+				id = matches[0][idx]
+			}
+			return enumval, id, nil
+		}
+		if len(matches) > 1 {
+			err := m.invalid(name)
+			err.Candidates = slices.Sorted(slices.Values(slices.Concat(matches...)))
+			err.reason = fmt.Sprintf("'%s' is ambiguous, could be %s",
+				name, quotedAll(matches))
+			return zero, "", err
 		}
 	}
-	// Oh no! An invalid textual enum value was specified, so let's generate
+	// Oh no! An invalid textual enum value was specified, so let's return
 	// some useful error explaining which textual representations are valid.
-	// We're ordering values by their canonical names in order to achieve a
-	// stable error message.
-	allids := []string{}
-	for _, ids := range m.m {
-		s := []string{}
-		for _, id := range ids {
-			s = append(s, "'"+id+"'")
+	// And in case the user just made a typo, let's try to be helpful and point
+	// out the closest matches.
+	err := m.invalid(name)
+	err.Suggestions = suggest(name, m.indices)
+	if len(err.Suggestions) > 0 {
+		s := make([]string, 0, len(err.Suggestions))
+		for _, suggestion := range err.Suggestions {
+			s = append(s, "'"+suggestion+"'")
 		}
-		allids = append(allids, strings.Join(s, "/"))
+		err.reason += "; did you mean " + strings.Join(s, " or ") + "?"
 	}
-	sort.Strings(allids)
-	var zero E
-	return zero, fmt.Errorf("must be %s", strings.Join(allids, ", "))
+	return zero, "", err
+}
+
+// invalid returns a new InvalidValueError for the specified input, with the
+// allowed enum values filled in.
+func (m enumMapper[E]) invalid(input string) *InvalidValueError {
+	return &InvalidValueError{
+		Input:   input,
+		Allowed: slices.Clone(m.allowed),
+		Index:   -1,
+		reason:  m.reason,
+	}
+}
+
+// Matches reports whether the user input matches the specified identifier.
+func (m enumMapper[E]) Matches(input, id string) bool {
+	for _, mi := range m.indices {
+		for _, k := range mi.index[mi.matcher.Normalize(input)] {
+			if k.id == id {
+				return mi.matcher.Match(input, id)
+			}
+		}
+	}
+	return false
+}
+
+// prefixed returns the enum values having identifiers starting with the
+// specified prefix, with the prefix getting normalized as well and ignoring
+// hidden identifiers. It returns one of the matching enum values, as well as
+// the matching identifiers grouped by enum value, with the groups sorted by
+// their first identifiers. The match is unique only if there's exactly one
+// group.
+func (m enumMapper[E]) prefixed(prefix string) (enumval E, matches [][]string) {
+	matching := map[E]map[string]struct{}{}
+	for _, mi := range m.indices {
+		prefix := mi.matcher.Normalize(prefix)
+		if prefix == "" {
+			continue
+		}
+		first, _ := slices.BinarySearchFunc(mi.keys, prefix, func(k indexKey[E], prefix string) int {
+			return strings.Compare(k.key, prefix)
+		})
+		for _, k := range mi.keys[first:] {
+			if !strings.HasPrefix(k.key, prefix) {
+				break
+			}
+			if k.hidden {
+				continue
+			}
+			enumval = k.enumval
+			if matching[k.enumval] == nil {
+				matching[k.enumval] = map[string]struct{}{}
+			}
+			matching[k.enumval][k.id] = struct{}{}
+		}
+	}
+	// Keep the matching identifiers of an enum value in their original order.
+	for val, ids := range matching {
+		matches = append(matches, slices.DeleteFunc(slices.Clone(m.m[val]), func(id string) bool {
+			_, ok := ids[id]
+			return !ok
+		}))
+	}
+	slices.SortFunc(matches, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return
+}
+
+// quotedAll returns the specified groups of identifiers in quotes and
+// separated by commas, such as “'bar'/'Bar', 'baz'”.
+func quotedAll(groups [][]string) string {
+	s := make([]string, 0, len(groups))
+	for _, ids := range groups {
+		s = append(s, quoted(ids))
+	}
+	return strings.Join(s, ", ")
+}
+
+// quoted returns the specified identifiers in quotes and separated by
+// slashes, such as “'bar'/'Bar'”.
+func quoted(ids []string) string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, "'"+id+"'")
+	}
+	return strings.Join(s, "/")
 }
 
-// Mapping returns the mapping of enum values to their names.
+// Mapping returns the (snapshot) mapping of enum values to their names.
 func (m enumMapper[E]) Mapping() EnumIdentifiers[E] {
 	return m.m
 }
+
+// Listed returns the mapping of enum values to their advertised names only,
+// leaving out enum values without any advertised names.
+func (m enumMapper[E]) Listed() EnumIdentifiers[E] {
+	return m.listed
+}
+
+// Visible returns all mapped enum values that aren't hidden, in order.
+func (m enumMapper[E]) Visible() []E {
+	visible := make([]E, 0, len(m.order))
+	for _, enumval := range m.order {
+		if slices.ContainsFunc(m.m[enumval], func(id string) bool {
+			return !m.isHidden(enumval, id)
+		}) {
+			visible = append(visible, enumval)
+		}
+	}
+	return visible
+}
+
+// MatchesKeyword reports whether the user input matches the specified
+// keyword, using the default Matcher.
+func (m enumMapper[E]) MatchesKeyword(input, keyword string) bool {
+	matcher := m.indices[len(m.indices)-1].matcher
+	return matcher.Normalize(input) == matcher.Normalize(keyword) &&
+		matcher.Match(input, keyword)
+}
+
+// Bare returns the bare enum value and true, if set. Otherwise, it returns
+// false.
+func (m enumMapper[E]) Bare() (E, bool) {
+	if m.bare == nil {
+		var zero E
+		return zero, false
+	}
+	return *m.bare, true
+}
+
+// helptext returns the tab-prefixed completion help text for the specified
+// enum value, if any, marking the bare enum value as implied. Otherwise, it
+// returns an empty string.
+func (m enumMapper[E]) helptext(help Help[E], enumval E) string {
+	text, ok := help[enumval]
+	if m.bare != nil && *m.bare == enumval {
+		if ok && text != "" {
+			text += " "
+		}
+		text, ok = text+impliedHelp, true
+	}
+	if !ok {
+		return ""
+	}
+	return "\t" + text
+}
+
+// Order returns all mapped enum values in order, as well as whether the order
+// has been explicitly declared.
+func (m enumMapper[E]) Order() ([]E, bool) {
+	return m.order, m.ordered
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchmarkIdentifiers returns a mapping of n enum values with a canonical
+// name and an alias each.
+func benchmarkIdentifiers(n int) EnumIdentifiers[int] {
+	mapping := EnumIdentifiers[int]{}
+	for i := range n {
+		mapping[i] = []string{fmt.Sprintf("value-%d", i), fmt.Sprintf("Alias%d", i)}
+	}
+	return mapping
+}
+
+func BenchmarkValueOf(b *testing.B) {
+	mapping := benchmarkIdentifiers(32)
+	for name, sensitivity := range map[string]Matcher{
+		"sensitive":   EnumCaseSensitive,
+		"insensitive": EnumCaseInsensitive,
+		"fold":        EnumCaseFold,
+		"foldfull":    EnumCaseFoldFull,
+	} {
+		mapper := newEnumMapper(mapping, sensitivity)
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := mapper.ValueOf("value-31"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValueOfInvalid(b *testing.B) {
+	mapper := newEnumMapper(benchmarkIdentifiers(32), EnumCaseInsensitive)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := mapper.ValueOf("foobar"); err == nil {
+			b.Fatal("expected error")
+		}
+	}
+}
+
+func BenchmarkSliceSet(b *testing.B) {
+	mapping := benchmarkIdentifiers(32)
+	ids := make([]string, 0, len(mapping))
+	for i := range len(mapping) {
+		ids = append(ids, mapping[i][0])
+	}
+	val := strings.Join(ids, ",")
+	var enums []int
+	flag := NewSlice(&enums, "values", mapping, EnumCaseInsensitive)
+	b.ReportAllocs()
+	for b.Loop() {
+		enums = nil
+		if err := flag.Set(val); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
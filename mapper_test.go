@@ -15,6 +15,8 @@
 package enumflag
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -42,13 +44,109 @@ var _ = Describe("enum name-value mapper", func() {
 	)
 
 	DescribeTable("returns helpful error when lookup fails",
-		func(name string, sensitivity EnumCaseSensitivity) {
+		func(name string, sensitivity EnumCaseSensitivity, expectedErr string) {
 			mapper := newEnumMapper(FooModeIdentifiersTest, sensitivity)
-			Expect(mapper.ValueOf(name)).Error().To(MatchError("must be 'bar'/'Bar', 'baz', 'foo'"))
+			Expect(mapper.ValueOf(name)).Error().To(MatchError(expectedErr))
 		},
-		Entry("fool", "fool", EnumCaseSensitive),
-		Entry("fool/i", "fool", EnumCaseInsensitive),
-		Entry("BAr", "BAr", EnumCaseSensitive),
+		Entry("fool", "fool", EnumCaseSensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'; did you mean 'foo'?"),
+		Entry("fool/i", "fool", EnumCaseInsensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'; did you mean 'foo'?"),
+		Entry("BAr", "BAr", EnumCaseSensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'; did you mean 'Bar'?"),
+		Entry("BAZZ/i", "BAZZ", EnumCaseInsensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'; did you mean 'baz'?"),
+		Entry("ba", "ba", EnumCaseSensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'; did you mean 'bar' or 'baz'?"),
+		Entry("fooboo", "fooboo", EnumCaseSensitive,
+			"must be 'bar'/'Bar', 'baz', 'foo'"),
 	)
 
+	It("works on a snapshot of the mapping", func() {
+		mapping := EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: {"bar"},
+		}
+		mapper := newEnumMapper(mapping, EnumCaseSensitive)
+		mapping[fmFoo][0] = "fool"
+		mapping[fmBaz] = []string{"baz"}
+		delete(mapping, fmBar)
+		Expect(mapper.ValueOf("foo")).To(Equal(fmFoo))
+		Expect(mapper.ValueOf("bar")).To(Equal(fmBar))
+		Expect(mapper.ValueOf("baz")).Error().To(HaveOccurred())
+		Expect(mapper.Lookup(fmFoo)).To(Equal([]string{"foo"}))
+		Expect(mapper.Mapping()).To(HaveLen(2))
+	})
+
+	It("returns the error details", func() {
+		mapper := newEnumMapper(FooModeIdentifiersTest, EnumCaseSensitive)
+		_, err := mapper.ValueOf("fool")
+		var ierr *InvalidValueError
+		Expect(errors.As(err, &ierr)).To(BeTrue())
+		Expect(ierr.Input).To(Equal("fool"))
+		Expect(ierr.Allowed).To(Equal([]string{"bar", "baz", "foo"}))
+		Expect(ierr.Candidates).To(BeEmpty())
+		Expect(ierr.Suggestions).To(Equal([]string{"foo"}))
+		Expect(ierr.Index).To(Equal(-1))
+		Expect(ierr.Offset).To(BeZero())
+	})
+
+	Context("abbreviations", func() {
+
+		var AbbrevIdentifiersTest = map[FooModeTest][]string{
+			fmFoo: {"foo", "foobar"},
+			fmBar: {"bar", "Bar"},
+			fmBaz: {"baz", "zab"},
+		}
+
+		DescribeTable("looks up value for unique prefix",
+			func(name string, sensitivity EnumCaseSensitivity, expectedValue FooModeTest) {
+				mapper := newEnumMapper(AbbrevIdentifiersTest, sensitivity, WithAbbreviations())
+				Expect(mapper.ValueOf(name)).To(Equal(expectedValue))
+			},
+			Entry("f", "f", EnumCaseSensitive, fmFoo),
+			Entry("foob", "foob", EnumCaseSensitive, fmFoo),
+			Entry("FO/i", "FO", EnumCaseInsensitive, fmFoo),
+			Entry("B", "B", EnumCaseSensitive, fmBar),
+			Entry("Ba", "Ba", EnumCaseSensitive, fmBar),
+			Entry("z", "z", EnumCaseSensitive, fmBaz),
+			Entry("exact match wins", "baz", EnumCaseSensitive, fmBaz),
+		)
+
+		DescribeTable("reports ambiguous prefixes",
+			func(name string, sensitivity EnumCaseSensitivity, expectedErr string) {
+				mapper := newEnumMapper(AbbrevIdentifiersTest, sensitivity, WithAbbreviations())
+				Expect(mapper.ValueOf(name)).Error().To(MatchError(expectedErr))
+			},
+			Entry("ba", "ba", EnumCaseSensitive, "'ba' is ambiguous, could be 'bar', 'baz'"),
+			Entry("BA/i", "BA", EnumCaseInsensitive, "'BA' is ambiguous, could be 'bar'/'Bar', 'baz'"),
+		)
+
+		DescribeTable("rejects non-matching prefixes",
+			func(name string) {
+				mapper := newEnumMapper(AbbrevIdentifiersTest, EnumCaseSensitive, WithAbbreviations())
+				Expect(mapper.ValueOf(name)).Error().To(MatchError(HavePrefix("must be ")))
+			},
+			Entry("empty", ""),
+			Entry("F", "F"),
+			Entry("fooo", "fooo"),
+		)
+
+		It("lists the candidates in the error details", func() {
+			mapper := newEnumMapper(AbbrevIdentifiersTest, EnumCaseInsensitive, WithAbbreviations())
+			_, err := mapper.ValueOf("BA")
+			var ierr *InvalidValueError
+			Expect(errors.As(err, &ierr)).To(BeTrue())
+			Expect(ierr.Input).To(Equal("BA"))
+			Expect(ierr.Candidates).To(Equal([]string{"Bar", "bar", "baz"}))
+			Expect(ierr.Suggestions).To(BeEmpty())
+		})
+
+		It("doesn't accept prefixes by default", func() {
+			mapper := newEnumMapper(AbbrevIdentifiersTest, EnumCaseSensitive)
+			Expect(mapper.ValueOf("f")).Error().To(HaveOccurred())
+		})
+
+	})
+
 })
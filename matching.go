@@ -0,0 +1,157 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Matcher decides which user input matches which enum identifiers. A Matcher
+// first normalizes identifiers as well as user input, and only identifiers
+// and user input with the same normalized form are considered to match. A
+// Matcher then gets the final say on whether the (non-normalized) user input
+// actually matches a candidate identifier.
+//
+// [EnumCaseSensitivity] and [EnumCaseFolding] values are the most basic
+// Matchers, and so are the [MatchPolicy] values. Multiple Matchers can be
+// chained using [ChainMatchers].
+type Matcher interface {
+	// Normalize returns the normalized form of an identifier or user input.
+	Normalize(s string) string
+	// Match reports whether the user input matches the identifier, given that
+	// both have the same normalized form.
+	Match(input, id string) bool
+}
+
+var _ Matcher = EnumCaseSensitive
+
+// Normalize returns the specified identifier or user input adjusted to the
+// case sensitivity.
+func (s EnumCaseSensitivity) Normalize(id string) string { return s.fold(id) }
+
+// Match always reports true, as the normalized form already decides whether
+// identifier and user input match.
+func (s EnumCaseSensitivity) Match(input, id string) bool { return true }
+
+var _ Matcher = EnumCaseFold
+
+// Normalize returns the specified identifier or user input case folded.
+func (f EnumCaseFolding) Normalize(id string) string { return f.fold(id) }
+
+// Match always reports true, as the normalized form already decides whether
+// identifier and user input match.
+func (f EnumCaseFolding) Match(input, id string) bool { return true }
+
+// MatchPolicy is a set of built-in Matchers for lenient matching of user
+// input; they can be combined with each other as well as with
+// [EnumCaseSensitivity] and [EnumCaseFolding] values using [ChainMatchers].
+type MatchPolicy uint8
+
+// Built-in lenient matching policies.
+//
+// MatchTrimSpace ignores any leading and trailing white space.
+//
+// MatchIgnoreSeparators ignores any “-”, “_”, and “.” separators, so that
+// “dry-run”, “dry_run”, and “dryrun” all match.
+//
+// MatchSmartCase matches case insensitively (using simple Unicode case
+// folding), unless the user input contains upper case characters; in the
+// latter case, user input is matched case sensitively.
+const (
+	MatchTrimSpace MatchPolicy = iota + 1
+	MatchIgnoreSeparators
+	MatchSmartCase
+)
+
+// Normalize returns the specified identifier or user input normalized
+// according to the policy.
+func (p MatchPolicy) Normalize(s string) string {
+	switch p {
+	case MatchTrimSpace:
+		return strings.TrimSpace(s)
+	case MatchIgnoreSeparators:
+		if !strings.ContainsAny(s, separatorRunes) {
+			return s
+		}
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(separatorRunes, r) {
+				return -1
+			}
+			return r
+		}, s)
+	case MatchSmartCase:
+		return simpleFold(s)
+	}
+	return s
+}
+
+// Match reports whether the user input matches the identifier. Only in case of
+// MatchSmartCase the decision depends on the user input containing upper case
+// characters or not.
+func (p MatchPolicy) Match(input, id string) bool {
+	if p != MatchSmartCase || !hasUpper(input) {
+		return true
+	}
+	return input == id
+}
+
+// separatorRunes are the separators ignored by MatchIgnoreSeparators.
+const separatorRunes = "-_."
+
+// hasUpper returns true if the specified string contains any upper or title
+// case characters.
+func hasUpper(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsUpper(r) || unicode.IsTitle(r)
+	}) >= 0
+}
+
+// ChainMatchers returns a Matcher that applies the specified Matchers in
+// order. For instance:
+//
+//	ChainMatchers(MatchTrimSpace, MatchIgnoreSeparators, EnumCaseInsensitive)
+//
+// The normalized form is the result of successively normalizing by each
+// Matcher. User input matches an identifier only if each Matcher agrees,
+// whereas each Matcher gets passed user input and identifier as normalized by
+// the Matchers preceding it.
+func ChainMatchers(matchers ...Matcher) Matcher {
+	return matcherChain(matchers)
+}
+
+// matcherChain applies multiple Matchers in order.
+type matcherChain []Matcher
+
+// Normalize returns the specified identifier or user input successively
+// normalized by all Matchers in the chain.
+func (c matcherChain) Normalize(s string) string {
+	for _, m := range c {
+		s = m.Normalize(s)
+	}
+	return s
+}
+
+// Match reports whether the user input matches the identifier according to all
+// Matchers in the chain.
+func (c matcherChain) Match(input, id string) bool {
+	for _, m := range c {
+		if !m.Match(input, id) {
+			return false
+		}
+		input, id = m.Normalize(input), m.Normalize(id)
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("matching", func() {
+
+	mapping := EnumIdentifiers[FooModeTest]{
+		fmFoo: {"dry-run"},
+		fmBar: {"Bar"},
+		fmBaz: {"baz"},
+	}
+
+	DescribeTable("normalizes",
+		func(matcher Matcher, s string, expected string) {
+			Expect(matcher.Normalize(s)).To(Equal(expected))
+		},
+		Entry(nil, MatchTrimSpace, " \tfoo bar\n", "foo bar"),
+		Entry(nil, MatchIgnoreSeparators, "dry-run_now.please", "dryrunnowplease"),
+		Entry(nil, MatchIgnoreSeparators, "dryrun", "dryrun"),
+		Entry(nil, MatchSmartCase, "DryRun", "dryrun"),
+		Entry(nil, EnumCaseSensitive, "DryRun", "DryRun"),
+		Entry(nil, ChainMatchers(MatchTrimSpace, MatchIgnoreSeparators, EnumCaseInsensitive),
+			" Dry-Run ", "dryrun"),
+	)
+
+	DescribeTable("matches smart case",
+		func(input string, id string, expected bool) {
+			Expect(MatchSmartCase.Match(input, id)).To(Equal(expected))
+		},
+		Entry(nil, "bar", "Bar", true),
+		Entry(nil, "Bar", "Bar", true),
+		Entry(nil, "BAR", "Bar", false),
+		Entry(nil, "Bar", "bar", false),
+	)
+
+	DescribeTable("looks up value for name",
+		func(name string, matcher Matcher, expected FooModeTest) {
+			mapper := newEnumMapper(mapping, matcher)
+			Expect(mapper.ValueOf(name)).To(Equal(expected))
+		},
+		Entry(nil, " baz ", MatchTrimSpace, fmBaz),
+		Entry(nil, "dry_run", MatchIgnoreSeparators, fmFoo),
+		Entry(nil, "dryrun", MatchIgnoreSeparators, fmFoo),
+		Entry(nil, "bar", MatchSmartCase, fmBar),
+		Entry(nil, "Bar", MatchSmartCase, fmBar),
+		Entry(nil, " DRY.RUN ", ChainMatchers(MatchTrimSpace, MatchIgnoreSeparators, EnumCaseInsensitive), fmFoo),
+		Entry(nil, " dry.run ", ChainMatchers(MatchTrimSpace, MatchIgnoreSeparators, MatchSmartCase), fmFoo),
+	)
+
+	DescribeTable("rejects invalid name",
+		func(name string, matcher Matcher) {
+			mapper := newEnumMapper(mapping, matcher)
+			_, err := mapper.ValueOf(name)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry(nil, " baz ", EnumCaseSensitive),
+		Entry(nil, "dry run", MatchIgnoreSeparators),
+		Entry(nil, "BAR", MatchSmartCase),
+		Entry(nil, " DRY.RUN ", ChainMatchers(MatchTrimSpace, MatchIgnoreSeparators, MatchSmartCase)),
+	)
+
+	It("suggests using the normalized forms", func() {
+		mapper := newEnumMapper(mapping, ChainMatchers(MatchIgnoreSeparators, EnumCaseInsensitive))
+		_, err := mapper.ValueOf("DRY_RUNN")
+		Expect(err).To(MatchError("must be 'Bar', 'baz', 'dry-run'; did you mean 'dry-run'?"))
+	})
+
+	Context("per-identifier overrides", func() {
+
+		It("matches overridden identifiers using their own matcher", func() {
+			mapper := newEnumMapper(mapping, EnumCaseInsensitive,
+				WithIdentifierMatcher(EnumCaseSensitive, "Bar"),
+				WithIdentifierMatcher(MatchIgnoreSeparators, "dry-run"))
+			Expect(mapper.ValueOf("Bar")).To(Equal(fmBar))
+			Expect(mapper.ValueOf("BAZ")).To(Equal(fmBaz))
+			Expect(mapper.ValueOf("dryrun")).To(Equal(fmFoo))
+			_, err := mapper.ValueOf("bar")
+			Expect(err).To(HaveOccurred())
+			_, err = mapper.ValueOf("DRYRUN")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("abbreviates overridden identifiers", func() {
+			mapper := newEnumMapper(mapping, EnumCaseInsensitive,
+				WithAbbreviations(),
+				WithIdentifierMatcher(MatchIgnoreSeparators, "dry-run"))
+			Expect(mapper.ValueOf("dryr")).To(Equal(fmFoo))
+			Expect(mapper.ValueOf("dry_r")).To(Equal(fmFoo))
+		})
+
+		It("panics on unmapped or nil overrides", func() {
+			var foomode FooModeTest = fmBaz
+			Expect(func() {
+				_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+					WithIdentifierMatcher(EnumCaseSensitive, "foo"))
+			}).To(PanicWith(MatchRegexp(`identifier 'foo' has a matcher but isn't mapped`)))
+			Expect(func() {
+				_ = New(&foomode, "mode", mapping, EnumCaseInsensitive,
+					WithIdentifierMatcher(nil, "baz"))
+			}).To(PanicWith(MatchRegexp(`identifiers 'baz' have a nil matcher`)))
+			Expect(func() {
+				_ = New(&foomode, "mode", mapping, nil)
+			}).To(PanicWith(MatchRegexp(`matcher must not be nil`)))
+		})
+
+		It("validates per matcher", func() {
+			m := EnumIdentifiers[FooModeTest]{
+				fmFoo: {"foo"},
+				fmBar: {"FOO"},
+			}
+			Expect(Validate(m, EnumCaseInsensitive)).To(HaveOccurred())
+			Expect(validate(m, EnumCaseInsensitive, newOptions([]Option{
+				WithIdentifierMatcher(EnumCaseSensitive, "FOO"),
+			}))).To(Succeed())
+		})
+
+	})
+
+	It("filters slice completions using the same matcher", func() {
+		var foomodes []FooModeTest
+		flag := NewSlice(&foomodes, "modes", mapping,
+			ChainMatchers(MatchIgnoreSeparators, MatchSmartCase))
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, _ := completor(&cobra.Command{}, nil, "dry_run,bar,")
+		Expect(completions).To(ConsistOf("dry_run,bar,baz"))
+		completions, _ = completor(&cobra.Command{}, nil, "DRY_RUN,")
+		Expect(completions).To(ConsistOf("DRY_RUN,dry-run", "DRY_RUN,Bar", "DRY_RUN,baz"))
+		completions, _ = completor(&cobra.Command{}, nil, "BAR,")
+		Expect(completions).To(ConsistOf("BAR,dry-run", "BAR,Bar", "BAR,baz"))
+	})
+
+})
@@ -0,0 +1,107 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("optional enum flags", func() {
+
+	It("stays nil until set", func() {
+		var level *levelTest
+		flag := NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		Expect(level).To(BeNil())
+		Expect(flag.String()).To(BeEmpty())
+		Expect(flag.Get()).To(BeNil())
+		_, ok := flag.GetOptionalValue()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("accepts the zero enum value as a legitimate choice", func() {
+		var level *levelTest
+		flag := NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("INFO")).To(Succeed())
+		Expect(level).NotTo(BeNil())
+		Expect(*level).To(Equal(lvlInfo))
+		Expect(flag.String()).To(Equal("info"))
+		v, ok := flag.GetOptionalValue()
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal(lvlInfo))
+
+		Expect(flag.Set("error")).To(Succeed())
+		Expect(*level).To(Equal(lvlError))
+	})
+
+	It("rejects invalid values", func() {
+		var level *levelTest
+		flag := NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		err := flag.Set("infoo")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*InvalidValueError).Type).To(Equal("level"))
+		Expect(level).To(BeNil())
+	})
+
+	It("resets to nil", func() {
+		var level *levelTest
+		flag := NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Set("warn")).To(Succeed())
+		flag.Reset()
+		Expect(level).To(BeNil())
+	})
+
+	It("shows no default in help", func() {
+		var level *levelTest
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().Var(NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive),
+			"level", "sets the level")
+		Expect(cmd.Flags().Lookup("level").DefValue).To(BeEmpty())
+		Expect(cmd.Flags().FlagUsages()).NotTo(ContainSubstring("default"))
+	})
+
+	It("completes", func() {
+		var level *levelTest
+		flag := NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(lvlDebug, lvlInfo, lvlWarn, lvlError, lvlFatal))
+		completions, directive := flag.value.NewCompletor(flag.names, nil)(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{"debug", "info", "warn", "8", "error", "fatal"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder))
+	})
+
+	It("doesn't return optional values for other enum flags", func() {
+		level := lvlWarn
+		flag := New(&level, "level", levelIdentifiersTest, EnumCaseInsensitive)
+		_, ok := flag.GetOptionalValue()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("panics on invalid parameters", func() {
+		Expect(func() { NewOptional[levelTest](nil, "level", levelIdentifiersTest, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewOptional requires flag to be a non-nil pointer`)))
+		var level *levelTest
+		Expect(func() { NewOptional(&level, "level", nil, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewOptional requires mapping not to be nil`)))
+		Expect(func() { NewOptional(&level, "level", levelIdentifiersTest, nil) }).
+			To(PanicWith(MatchRegexp(`NewOptional requires a valid mapping`)))
+		l := lvlWarn
+		level = &l
+		Expect(func() { NewOptional(&level, "level", levelIdentifiersTest, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewOptional requires flag to reference a nil pointer`)))
+	})
+
+})
@@ -0,0 +1,232 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+// Option configures optional behavior of an enum flag value. Options are
+// passed to the enum flag value constructors, such as [New] and [NewSlice].
+type Option func(*options)
+
+// options collects the optional settings for an enum flag value while it
+// gets constructed.
+type options struct {
+	abbreviations bool              // accept unique prefixes of enum identifiers.
+	overrides     []matcherOverride // per-identifier Matchers, in order.
+	numericCodes  bool              // accept numeric codes of mapped enum values.
+
+	deprecatedIds    map[string]deprecation // deprecated identifiers.
+	deprecatedValues map[any]deprecation    // deprecated enum values of type E.
+	warn             func(warning string)   // warning sink, nil for stderr.
+
+	hiddenIds    []string // hidden identifiers.
+	hiddenValues []any    // hidden enum values of type E.
+
+	order []any // declared order of enum values of type E.
+
+	editOperators bool // accept "+foo" and "-foo" slice edit operators.
+
+	all  metaKeyword // slice meta keyword for all enum values.
+	none metaKeyword // slice meta keyword for no enum values.
+
+	minCount     int     // minimum number of slice enum values.
+	maxCount     int     // maximum number of slice enum values, if non-zero.
+	maxInputSize int     // maximum slice input size in bytes, if non-zero.
+	exclusive    [][]any // sets of mutually exclusive enum values of type E.
+	standalone   []any   // enum values of type E not to be combined with others.
+
+	duplicates  DuplicatePolicy // handling of duplicate slice enum values.
+	orderPolicy OrderPolicy     // order of slice enum values.
+
+	accumulation AccumulationPolicy // handling of repeated slice enum flags.
+
+	separator     string             // separator between slice elements.
+	quoting       bool               // CSV-style quoting of slice elements?
+	emptyElements EmptyElementPolicy // handling of empty slice elements.
+	parseable     bool               // parseable String of slice enum flags?
+
+	bare any // bare enum value of type E, if any.
+
+	keyOpts   []Option // options specific to map enum keys.
+	valueOpts []Option // options specific to map enum values.
+}
+
+// matcherOverride assigns a specific Matcher to a set of identifiers.
+type matcherOverride struct {
+	matcher Matcher
+	ids     []string
+}
+
+// newOptions returns the optional settings resulting from applying the
+// specified options in order.
+func newOptions(opts []Option) options {
+	o := options{separator: ","}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// matcherIndices returns the indices into the matcher overrides for all
+// overridden identifiers. If an identifier is overridden multiple times, the
+// last override wins.
+func (o options) matcherIndices() map[string]int {
+	indices := map[string]int{}
+	for idx, override := range o.overrides {
+		for _, id := range override.ids {
+			indices[id] = idx
+		}
+	}
+	return indices
+}
+
+// WithAbbreviations accepts any unique prefix of an enum identifier (as
+// normalized by the configured [Matcher]) as that identifier, such as “--mode=ba” for
+// “--mode=bar”. All identifiers, canonical as well as aliases, are taken into
+// account. A prefix is unique as long as all identifiers it matches belong to
+// the same enum value. Exact matches always take precedence over prefix
+// matches.
+func WithAbbreviations() Option {
+	return func(o *options) {
+		o.abbreviations = true
+	}
+}
+
+// WithIdentifierMatcher matches the specified identifiers using the specified
+// Matcher instead of the Matcher passed to the enum flag value constructor. For
+// instance, to only accept “--mode=Foo” but not “--mode=foo” while otherwise
+// matching case-insensitively:
+//
+//	enumflag.New(&mode, "mode", modeIds, enumflag.EnumCaseInsensitive,
+//	    enumflag.WithIdentifierMatcher(enumflag.EnumCaseSensitive, "Foo"))
+//
+// Identifiers with overridden Matchers take precedence over the other
+// identifiers when matching user input. The enum flag value constructors panic
+// if an identifier isn't part of the mapping, or if matcher is nil.
+func WithIdentifierMatcher(matcher Matcher, ids ...string) Option {
+	return func(o *options) {
+		o.overrides = append(o.overrides, matcherOverride{
+			matcher: matcher,
+			ids:     ids,
+		})
+	}
+}
+
+// WithNumericCodes additionally accepts the numeric codes of mapped enum values
+// in place of their identifiers, such as “--level=3” for “--level=warn”. Codes
+// can be specified in decimal as well as in hexadecimal notation, such as
+// “0x03”, and can be negative. Numeric codes of unmapped enum values are
+// rejected, and the textual representation of an enum flag value still uses its
+// canonical identifier. Identifiers always take precedence over numeric codes.
+//
+// The enum flag value constructors panic when passing this option for enum
+// types that aren't integer-kinded.
+func WithNumericCodes() Option {
+	return func(o *options) {
+		o.numericCodes = true
+	}
+}
+
+// WithHiddenIdentifiers hides the specified identifiers, such as identifiers
+// for internal or debugging use. Hidden identifiers are still accepted when
+// specified in full, but they aren't offered in completions, listed in error
+// messages and help, nor matched as abbreviations.
+//
+// The enum flag value constructors panic if an identifier isn't mapped.
+func WithHiddenIdentifiers(ids ...string) Option {
+	return func(o *options) {
+		o.hiddenIds = append(o.hiddenIds, ids...)
+	}
+}
+
+// WithHiddenValues hides the specified enum values with all their identifiers,
+// such as enum values for internal or debugging use. The identifiers of hidden
+// enum values are still accepted when specified in full, but they aren't
+// offered in completions, listed in error messages and help, nor matched as
+// abbreviations.
+//
+// The enum flag value constructors panic if an enum value isn't mapped, or if
+// it isn't of the enum type of the flag value.
+func WithHiddenValues[E comparable](enumvals ...E) Option {
+	return func(o *options) {
+		for _, enumval := range enumvals {
+			o.hiddenValues = append(o.hiddenValues, enumval)
+		}
+	}
+}
+
+// WithEditOperators enables editing the current enum values of slice enum
+// flags, including any default values: “--features=+foo” adds “foo”,
+// “--features=-bar” removes “bar”, and “--features=+foo,-bar” does both. An
+// explicit “--features=” clears the slice. Enum values without any prefix are
+// added, too, if any other enum value is prefixed; if no enum value is
+// prefixed, the usual slice semantics apply. Please note that with numeric
+// codes enabled, negative codes need to be prefixed with an edit operator,
+// such as “+-4”.
+//
+// This option only applies to slice enum flags; the enum flag value
+// constructors panic if any identifier starts with “+” or “-”.
+func WithEditOperators() Option {
+	return func(o *options) {
+		o.editOperators = true
+	}
+}
+
+// metaKeyword is an optional meta keyword of slice enum flags, together with
+// its help text for completion.
+type metaKeyword struct {
+	keyword string
+	help    string
+}
+
+// matches reports whether the user input matches the meta keyword, if set,
+// using the specified keyword matching function.
+func (k metaKeyword) matches(input string, match func(input, keyword string) bool) bool {
+	return k.keyword != "" && match(input, k.keyword)
+}
+
+// helptext returns the tab-prefixed help text for completing the meta
+// keyword, if any; otherwise, it returns an empty string.
+func (k metaKeyword) helptext() string {
+	if k.help == "" {
+		return ""
+	}
+	return "\t" + k.help
+}
+
+// WithAllKeyword enables the specified meta keyword, such as “all”, for slice
+// enum flags, expanding to all enum values except for hidden ones. The meta
+// keyword gets offered in completions with the specified help text. For
+// instance, with [WithEditOperators] users can then specify “--checks=all,-slow”.
+//
+// This option only applies to slice enum flags; the enum flag value
+// constructors panic if the keyword matches any identifier.
+func WithAllKeyword(keyword string, help string) Option {
+	return func(o *options) {
+		o.all = metaKeyword{keyword: keyword, help: help}
+	}
+}
+
+// WithNoneKeyword enables the specified meta keyword, such as “none”, for
+// slice enum flags, resulting in an empty slice. Any enum values preceding the
+// meta keyword are ignored, while subsequent enum values are kept, so
+// “--checks=none,fast” results in just the “fast” enum value. The meta keyword
+// gets offered in completions with the specified help text.
+//
+// This option only applies to slice enum flags; the enum flag value
+// constructors panic if the keyword matches any identifier.
+func WithNoneKeyword(keyword string, help string) Option {
+	return func(o *options) {
+		o.none = metaKeyword{keyword: keyword, help: help}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"slices"
+	"strings"
+)
+
+// EnumIdentifier is an enumeration value together with its textual
+// representations (~identifiers), with the first identifier being the
+// canonical one.
+type EnumIdentifier[E comparable] struct {
+	Value       E
+	Identifiers []string
+}
+
+// OrderedEnumIdentifiers maps enumeration values to their corresponding
+// textual representations (~identifiers), similar to [EnumIdentifiers], but
+// additionally declares the order of the enumeration values. For instance:
+//
+//	var LevelIds = enumflag.OrderedEnumIdentifiers[Level]{
+//	    {Trace, []string{"trace"}},
+//	    {Debug, []string{"debug"}},
+//	    {Info, []string{"info"}},
+//	}
+//
+// Pass the ordered mapping to any enum flag value constructor using its
+// [OrderedEnumIdentifiers.Mapping] and [OrderedEnumIdentifiers.Order] methods:
+//
+//	enumflag.New(&level, "level", LevelIds.Mapping(), enumflag.EnumCaseInsensitive,
+//	    LevelIds.Order())
+//
+// Completions then follow the declared order, and so do error messages.
+type OrderedEnumIdentifiers[E comparable] []EnumIdentifier[E]
+
+// Mapping returns the enum values and their identifiers as [EnumIdentifiers].
+// In case the same enum value is declared multiple times, the identifiers of
+// all its declarations get concatenated.
+func (o OrderedEnumIdentifiers[E]) Mapping() EnumIdentifiers[E] {
+	mapping := make(EnumIdentifiers[E], len(o))
+	for _, entry := range o {
+		mapping[entry.Value] = append(mapping[entry.Value], entry.Identifiers...)
+	}
+	return mapping
+}
+
+// Order returns an option declaring the order of the enum values as passed to
+// the enum flag value constructors, see [WithOrder].
+func (o OrderedEnumIdentifiers[E]) Order() Option {
+	enumvals := make([]E, 0, len(o))
+	for _, entry := range o {
+		enumvals = append(enumvals, entry.Value)
+	}
+	return WithOrder(enumvals...)
+}
+
+// WithOrder declares the order of enum values, so that completions and error
+// messages list the enum values in this order instead of alphabetically.
+// Completions additionally ask the shell to keep this order. Enum values not
+// included are listed after the ordered ones, sorted alphabetically.
+//
+// The enum flag value constructors panic if an enum value isn't mapped, or if
+// it isn't of the enum type of the flag value.
+func WithOrder[E comparable](enumvals ...E) Option {
+	return func(o *options) {
+		for _, enumval := range enumvals {
+			o.order = append(o.order, enumval)
+		}
+	}
+}
+
+// orderOf returns all mapped enum values in their declared order, followed by
+// any remaining enum values, sorted by their first advertised (or otherwise
+// canonical) identifiers. It additionally returns true if an order has been
+// declared.
+func orderOf[E comparable](mapping, listed EnumIdentifiers[E], declared []any) ([]E, bool) {
+	order := make([]E, 0, len(mapping))
+	seen := make(map[E]struct{}, len(mapping))
+	for _, enumval := range declared {
+		enumval, ok := enumval.(E)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[enumval]; ok || len(mapping[enumval]) == 0 {
+			continue
+		}
+		seen[enumval] = struct{}{}
+		order = append(order, enumval)
+	}
+	remaining := make([]E, 0, len(mapping)-len(order))
+	for enumval, ids := range mapping {
+		if _, ok := seen[enumval]; ok || len(ids) == 0 {
+			continue
+		}
+		remaining = append(remaining, enumval)
+	}
+	sortkey := func(enumval E) string {
+		if ids := listed[enumval]; len(ids) > 0 {
+			return ids[0]
+		}
+		return mapping[enumval][0]
+	}
+	slices.SortFunc(remaining, func(a, b E) int {
+		return strings.Compare(sortkey(a), sortkey(b))
+	})
+	return append(order, remaining...), len(declared) > 0
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ordered mappings", func() {
+
+	orderedLevels := OrderedEnumIdentifiers[levelTest]{
+		{lvlDebug, []string{"debug", "dbg"}},
+		{lvlInfo, []string{"info"}},
+		{lvlWarn, []string{"warn", "warning"}},
+		{lvlError, []string{"error"}},
+		{lvlFatal, []string{"fatal"}},
+	}
+
+	It("converts into mapping and order", func() {
+		Expect(orderedLevels.Mapping()).To(Equal(EnumIdentifiers[levelTest]{
+			lvlDebug: {"debug", "dbg"},
+			lvlInfo:  {"info"},
+			lvlWarn:  {"warn", "warning"},
+			lvlError: {"error"},
+			lvlFatal: {"fatal"},
+		}))
+		var o options
+		orderedLevels.Order()(&o)
+		Expect(o.order).To(Equal([]any{lvlDebug, lvlInfo, lvlWarn, lvlError, lvlFatal}))
+	})
+
+	It("orders remaining enum values alphabetically", func() {
+		m := newEnumMapper(orderedLevels.Mapping(), EnumCaseInsensitive,
+			WithOrder(lvlWarn, lvlInfo, lvlWarn))
+		order, ordered := m.Order()
+		Expect(ordered).To(BeTrue())
+		Expect(order).To(Equal([]levelTest{lvlWarn, lvlInfo, lvlDebug, lvlError, lvlFatal}))
+
+		m = newEnumMapper(orderedLevels.Mapping(), EnumCaseInsensitive)
+		order, ordered = m.Order()
+		Expect(ordered).To(BeFalse())
+		Expect(order).To(Equal([]levelTest{lvlDebug, lvlError, lvlFatal, lvlInfo, lvlWarn}))
+	})
+
+	It("lists enum values in order in errors and help", func() {
+		level := lvlInfo
+		flag := New(&level, "level", orderedLevels.Mapping(), EnumCaseInsensitive,
+			orderedLevels.Order())
+		err := flag.Set("verbose")
+		Expect(err).To(MatchError(
+			"must be 'debug'/'dbg', 'info', 'warn'/'warning', 'error', 'fatal'"))
+		Expect(err.(*InvalidValueError).Allowed).To(Equal(
+			[]string{"debug", "info", "warn", "error", "fatal"}))
+		Expect(flag.Allowed()).To(Equal(
+			[]string{"debug", "info", "warn", "error", "fatal"}))
+	})
+
+	It("completes in order", func() {
+		level := lvlInfo
+		flag := New(&level, "level", orderedLevels.Mapping(), EnumCaseInsensitive,
+			orderedLevels.Order(),
+			WithHiddenValues(lvlFatal))
+		completions, directive := flag.value.NewCompletor(flag.names, Help[levelTest]{
+			lvlInfo: "informational",
+		})(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{
+			"debug", "dbg", "info\tinformational", "warn", "warning", "error"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder))
+
+		var levels []levelTest
+		slice := NewSlice(&levels, "levels", orderedLevels.Mapping(), EnumCaseInsensitive,
+			orderedLevels.Order())
+		completions, directive = slice.value.NewCompletor(slice.names, nil)(&cobra.Command{}, nil, "warn,dbg,")
+		Expect(completions).To(Equal([]string{
+			"warn,dbg,debug", "warn,dbg,info", "warn,dbg,warning", "warn,dbg,error", "warn,dbg,fatal"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp |
+			cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveKeepOrder))
+	})
+
+	It("doesn't keep order when unordered", func() {
+		level := lvlInfo
+		flag := New(&level, "level", orderedLevels.Mapping(), EnumCaseInsensitive)
+		completions, directive := flag.value.NewCompletor(flag.names, nil)(&cobra.Command{}, nil, "")
+		Expect(completions).To(Equal([]string{
+			"debug", "dbg", "error", "fatal", "info", "warn", "warning"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+	})
+
+	It("panics on invalid orders", func() {
+		level := lvlInfo
+		Expect(func() {
+			_ = New(&level, "level", orderedLevels.Mapping(), EnumCaseInsensitive,
+				WithOrder(levelTest(1)))
+		}).To(PanicWith(MatchRegexp(`ordered enum value 1 isn't mapped`)))
+		Expect(func() {
+			_ = New(&level, "level", orderedLevels.Mapping(), EnumCaseInsensitive,
+				WithOrder(1))
+		}).To(PanicWith(MatchRegexp(`ordered enum value 1 is of type int instead of enumflag.levelTest`)))
+	})
+
+})
@@ -38,6 +38,16 @@ var FooModeIdentifiersTest = map[FooModeTest][]string{
 	fmBaz: {"baz"},
 }
 
+// newFooModesTest returns a new slice enum flag value for the specified
+// FooModeTest slice, in the order fmFoo, fmBar, fmBaz unless the options
+// declare an order of their own.
+func newFooModesTest(foomodes *[]FooModeTest, opts ...Option) *EnumSliceFlagValue[FooModeTest] {
+	if len(newOptions(opts).order) == 0 {
+		opts = append([]Option{WithOrder(fmFoo, fmBar, fmBaz)}, opts...)
+	}
+	return NewSlice(foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive, opts...)
+}
+
 var FooModeHelp = map[FooModeTest]string{
 	fmFoo: "foo it",
 	fmBar: "bar IT!",
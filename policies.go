@@ -0,0 +1,265 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// DuplicatePolicy specifies how slice enum flags handle duplicate enum
+// values.
+type DuplicatePolicy uint8
+
+// Duplicate policies for slice enum flags; see [WithDuplicates]. Without any
+// duplicate policy, duplicate enum values are kept when specified in the same
+// flag occurrence, such as “--x=foo,foo”, while enum values already present
+// don't get merged again.
+//
+// KeepDuplicates always keeps duplicate enum values, even when merging.
+//
+// DropDuplicates silently drops duplicate enum values, keeping only the first
+// occurrence.
+//
+// RejectDuplicates rejects duplicate enum values with a [*ConstraintError].
+const (
+	KeepDuplicates DuplicatePolicy = iota + 1
+	DropDuplicates
+	RejectDuplicates
+)
+
+// OrderPolicy specifies the order of the enum values of slice enum flags.
+type OrderPolicy uint8
+
+// Order policies for slice enum flags; see [WithOrderPolicy].
+//
+// InputOrder keeps the enum values in the order as specified by the user,
+// with merged enum values getting appended. This is the default.
+//
+// MappingOrder orders the enum values in the declared order of the mapping
+// (see [WithOrder]), or otherwise sorted by their canonical names.
+//
+// SortedOrder sorts the enum values by their numerical or string values; it
+// requires enum types that are integer, floating point, or string kinded.
+const (
+	InputOrder OrderPolicy = iota
+	MappingOrder
+	SortedOrder
+)
+
+// AccumulationPolicy specifies how slice enum flags handle being specified
+// multiple times.
+type AccumulationPolicy uint8
+
+// Accumulation policies for slice enum flags; see [WithAccumulation].
+//
+// MergeRepeats replaces any default enum values on the first Set, and merges
+// the enum values of all subsequent Sets. This is the default.
+//
+// ReplaceRepeats lets the last occurrence win, replacing the enum values of
+// any earlier occurrences. Edit operators, if enabled, still edit the current
+// enum values.
+//
+// RejectRepeats rejects repeated flags with a [*ConstraintError].
+const (
+	MergeRepeats AccumulationPolicy = iota
+	ReplaceRepeats
+	RejectRepeats
+)
+
+// WithAccumulation sets the policy for slice enum flags being specified
+// multiple times. Only successful Sets count as occurrences, in line with
+// the Changed state of a [github.com/spf13/pflag.Flag]. [EnumFlagValue.Reset]
+// forgets about any earlier occurrences.
+//
+// The enum flag value constructors panic if the policy is unknown.
+func WithAccumulation(policy AccumulationPolicy) Option {
+	return func(o *options) {
+		o.accumulation = policy
+	}
+}
+
+// WithDuplicates sets the policy for handling duplicate enum values of slice
+// enum flags.
+//
+// The enum flag value constructors panic if the policy is unknown, or if
+// rejecting duplicates and the enum slice variable already contains duplicate
+// enum values.
+func WithDuplicates(policy DuplicatePolicy) Option {
+	return func(o *options) {
+		o.duplicates = policy
+	}
+}
+
+// WithOrderPolicy sets the order of the enum values of slice enum flags. The
+// order applies to the enum slice variable after each Set, as well as to the
+// enum values returned by [EnumFlagValue.Get], [EnumFlagValue.GetSliceValue],
+// and [EnumFlagValue.String].
+//
+// The enum flag value constructors panic if the policy is unknown, or if
+// sorting is requested for an enum type that isn't integer, floating point,
+// or string kinded.
+func WithOrderPolicy(policy OrderPolicy) Option {
+	return func(o *options) {
+		o.orderPolicy = policy
+	}
+}
+
+// slicePolicy combines the duplicate and order policies of slice enum flags.
+type slicePolicy[E comparable] struct {
+	duplicates   DuplicatePolicy
+	order        OrderPolicy
+	accumulation AccumulationPolicy
+	rank         map[E]int        // positions of enum values in mapping order.
+	compare      func(a, b E) int // for sorting, if E is ordered.
+}
+
+// newSlicePolicy returns the slice policy configured in the specified options,
+// ranking enum values according to the mapper's order.
+func newSlicePolicy[E comparable](o options, names enumMapper[E]) slicePolicy[E] {
+	p := slicePolicy[E]{
+		duplicates:   o.duplicates,
+		order:        o.orderPolicy,
+		accumulation: o.accumulation,
+	}
+	switch p.order {
+	case MappingOrder:
+		order, _ := names.Order()
+		p.rank = make(map[E]int, len(order))
+		for pos, enumval := range order {
+			p.rank[enumval] = pos
+		}
+	case SortedOrder:
+		p.compare, _ = comparator[E]()
+	}
+	return p
+}
+
+// validatePolicies checks the policies in the specified options as well as
+// the enum slice variable for problems, returning an error describing all
+// problems found, if any.
+func validatePolicies[E comparable](enumvals []E, o options) error {
+	problems := []string{}
+	if o.duplicates > RejectDuplicates {
+		problems = append(problems, fmt.Sprintf("unknown duplicate policy %d", o.duplicates))
+	}
+	if o.duplicates == RejectDuplicates {
+		if dupe, ok := duplicate(enumvals); ok {
+			problems = append(problems, fmt.Sprintf("duplicate enum value %v", dupe))
+		}
+	}
+	if o.accumulation > RejectRepeats {
+		problems = append(problems, fmt.Sprintf("unknown accumulation policy %d", o.accumulation))
+	}
+	switch o.orderPolicy {
+	case InputOrder, MappingOrder:
+	case SortedOrder:
+		if _, ok := comparator[E](); !ok {
+			var zero E
+			problems = append(problems, fmt.Sprintf(
+				"sorted order requires an ordered enum type, but %T isn't", zero))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown order policy %d", o.orderPolicy))
+	}
+	return problemsError(problems)
+}
+
+// active returns true if any policy has been set that requires normalizing
+// enum values.
+func (p slicePolicy[E]) active() bool {
+	return p.duplicates == DropDuplicates || p.order != InputOrder
+}
+
+// mergesPresent returns true if merging enum values already present is a
+// no-op, instead of keeping or rejecting them as duplicates.
+func (p slicePolicy[E]) mergesPresent() bool {
+	return p.duplicates != KeepDuplicates && p.duplicates != RejectDuplicates
+}
+
+// normalize returns the specified enum values with duplicates dropped and
+// ordered, as required by the policies. normalize modifies the passed enum
+// values in place.
+func (p slicePolicy[E]) normalize(enumvals []E) []E {
+	if p.duplicates == DropDuplicates {
+		seen := make(map[E]struct{}, len(enumvals))
+		enumvals = slices.DeleteFunc(enumvals, func(enumval E) bool {
+			if _, ok := seen[enumval]; ok {
+				return true
+			}
+			seen[enumval] = struct{}{}
+			return false
+		})
+	}
+	switch p.order {
+	case MappingOrder:
+		rank := func(enumval E) int {
+			if pos, ok := p.rank[enumval]; ok {
+				return pos
+			}
+			return len(p.rank) // unmapped enum values go last.
+		}
+		slices.SortStableFunc(enumvals, func(a, b E) int {
+			return rank(a) - rank(b)
+		})
+	case SortedOrder:
+		if p.compare != nil {
+			slices.SortStableFunc(enumvals, p.compare)
+		}
+	}
+	return enumvals
+}
+
+// duplicate returns the first duplicate enum value and true, if there is any.
+// Otherwise, it returns false.
+func duplicate[E comparable](enumvals []E) (E, bool) {
+	seen := make(map[E]struct{}, len(enumvals))
+	for _, enumval := range enumvals {
+		if _, ok := seen[enumval]; ok {
+			return enumval, true
+		}
+		seen[enumval] = struct{}{}
+	}
+	var zero E
+	return zero, false
+}
+
+// comparator returns a function comparing enum values of type E by their
+// numerical or string values, and true if E is integer, floating point, or
+// string kinded. Otherwise, it returns false.
+func comparator[E comparable]() (func(a, b E) int, bool) {
+	var zero E
+	switch reflect.ValueOf(zero).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b E) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b E) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}, true
+	case reflect.Float32, reflect.Float64:
+		return func(a, b E) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}, true
+	case reflect.String:
+		return func(a, b E) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}, true
+	}
+	return nil, false
+}
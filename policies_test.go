@@ -0,0 +1,263 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("slice policies", func() {
+
+	Context("duplicates", func() {
+
+		It("keeps duplicates by default only within the same flag", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes)
+			Expect(flag.Set("foo,foo")).To(Succeed())
+			Expect(flag.Set("foo,bar")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmFoo, fmBar}))
+		})
+
+		It("keeps duplicates", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes, WithDuplicates(KeepDuplicates))
+			Expect(flag.Set("foo,foo")).To(Succeed())
+			Expect(flag.Set("foo")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmFoo, fmFoo}))
+			Expect(flag.String()).To(Equal("[foo,foo,foo]"))
+		})
+
+		It("drops duplicates", func() {
+			foomodes := []FooModeTest{fmBar, fmBar}
+			flag := newFooModesTest(&foomodes, WithDuplicates(DropDuplicates))
+			Expect(foomodes).To(Equal([]FooModeTest{fmBar}))
+			Expect(flag.Set("baz,foo,Baz,bar")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo, fmBar}))
+			Expect(flag.Set("foo,bar")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo, fmBar}))
+		})
+
+		It("rejects duplicates", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes, WithDuplicates(RejectDuplicates))
+			err := flag.Set("foo,bar,Foo")
+			Expect(err).To(MatchError("'foo' must not be specified more than once"))
+			var cerr *ConstraintError
+			Expect(errors.As(err, &cerr)).To(BeTrue())
+			Expect(cerr.Type).To(Equal("modes"))
+			Expect(cerr.Kind).To(Equal(DuplicateConstraint))
+			Expect(cerr.Duplicate).To(Equal("foo"))
+			Expect(foomodes).To(BeEmpty())
+
+			Expect(flag.Set("foo,bar")).To(Succeed())
+			Expect(flag.Set("bar")).To(MatchError("'bar' must not be specified more than once"))
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar}))
+		})
+
+		It("keeps edits idempotent when rejecting duplicates", func() {
+			foomodes := []FooModeTest{fmFoo}
+			flag := newFooModesTest(&foomodes, WithDuplicates(RejectDuplicates), WithEditOperators())
+			Expect(flag.Set("+foo,+bar")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar}))
+		})
+
+		It("panics on duplicate defaults when rejecting duplicates", func() {
+			foomodes := []FooModeTest{fmFoo, fmFoo}
+			Expect(func() { newFooModesTest(&foomodes, WithDuplicates(RejectDuplicates)) }).
+				To(PanicWith(MatchRegexp(`duplicate enum value 1`)))
+		})
+
+		It("panics on unknown duplicate policies", func() {
+			var foomodes []FooModeTest
+			Expect(func() { newFooModesTest(&foomodes, WithDuplicates(DuplicatePolicy(42))) }).
+				To(PanicWith(MatchRegexp(`unknown duplicate policy 42`)))
+		})
+
+	})
+
+	Context("order", func() {
+
+		It("keeps the input order by default", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes)
+			Expect(flag.Set("baz,foo")).To(Succeed())
+			Expect(flag.Set("bar")).To(Succeed())
+			Expect(flag.GetSliceValue()).To(Equal([]FooModeTest{fmBaz, fmFoo, fmBar}))
+		})
+
+		It("orders by mapping declaration", func() {
+			foomodes := []FooModeTest{fmFoo, fmBaz}
+			flag := newFooModesTest(&foomodes, WithOrderPolicy(MappingOrder), WithOrder(fmBaz, fmBar, fmFoo))
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+			Expect(flag.Set("foo,bar")).To(Succeed())
+			Expect(flag.Set("baz")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmBar, fmFoo}))
+			Expect(flag.String()).To(Equal("[baz,bar,foo]"))
+		})
+
+		It("orders by canonical names without declared order", func() {
+			var foomodes []FooModeTest
+			flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithOrderPolicy(MappingOrder))
+			Expect(flag.Set("foo,baz,bar")).To(Succeed())
+			Expect(flag.String()).To(Equal("[bar,baz,foo]"))
+		})
+
+		It("sorts by enum values", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes, WithOrderPolicy(SortedOrder), WithDuplicates(DropDuplicates))
+			Expect(flag.Set("baz,foo,bar,foo")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar, fmBaz}))
+			Expect(flag.Get()).To(Equal([]FooModeTest{fmFoo, fmBar, fmBaz}))
+		})
+
+		It("orders externally modified enum values when getting them", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes, WithOrderPolicy(SortedOrder))
+			foomodes = []FooModeTest{fmBaz, fmFoo}
+			Expect(flag.GetSliceValue()).To(Equal([]FooModeTest{fmFoo, fmBaz}))
+			Expect(flag.String()).To(Equal("[foo,baz]"))
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		})
+
+		It("panics when sorting unordered enum types", func() {
+			type point struct{ x, y int }
+			var points []point
+			Expect(func() {
+				NewSlice(&points, "points", EnumIdentifiers[point]{{1, 2}: {"one"}}, EnumCaseSensitive,
+					WithOrderPolicy(SortedOrder))
+			}).To(PanicWith(MatchRegexp(`sorted order requires an ordered enum type, but .*point isn't`)))
+		})
+
+		It("panics on unknown order policies", func() {
+			var foomodes []FooModeTest
+			Expect(func() { newFooModesTest(&foomodes, WithOrderPolicy(OrderPolicy(42))) }).
+				To(PanicWith(MatchRegexp(`unknown order policy 42`)))
+		})
+
+	})
+
+	It("sorts floating point and string enum values", func() {
+		floats := []float64{2.5, -1}
+		NewSlice(&floats, "floats", EnumIdentifiers[float64]{2.5: {"high"}, -1: {"low"}}, EnumCaseSensitive,
+			WithOrderPolicy(SortedOrder))
+		Expect(floats).To(Equal([]float64{-1, 2.5}))
+
+		strs := []string{"z", "a"}
+		NewSlice(&strs, "strs", EnumIdentifiers[string]{"z": {"zed"}, "a": {"ay"}}, EnumCaseSensitive,
+			WithOrderPolicy(SortedOrder))
+		Expect(strs).To(Equal([]string{"a", "z"}))
+
+		uints := []uint8{7, 3}
+		NewSlice(&uints, "uints", EnumIdentifiers[uint8]{7: {"seven"}, 3: {"three"}}, EnumCaseSensitive,
+			WithOrderPolicy(SortedOrder))
+		Expect(uints).To(Equal([]uint8{3, 7}))
+	})
+
+	Context("accumulation", func() {
+
+		parse := func(flag *EnumSliceFlagValue[FooModeTest], args ...string) (*cobra.Command, error) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Var(flag, "modes", "")
+			return cmd, cmd.Flags().Parse(args)
+		}
+
+		It("merges repeated flags by default", func() {
+			foomodes := []FooModeTest{fmBaz}
+			_, err := parse(newFooModesTest(&foomodes), "--modes=foo", "--modes=bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar}))
+		})
+
+		It("lets the last occurrence win", func() {
+			foomodes := []FooModeTest{fmBaz}
+			flag := newFooModesTest(&foomodes, WithAccumulation(ReplaceRepeats), WithEditOperators())
+			cmd, err := parse(flag, "--modes=foo", "--modes=bar,baz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd.Flags().Lookup("modes").Changed).To(BeTrue())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBar, fmBaz}))
+			Expect(flag.Set("+foo")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBar, fmBaz, fmFoo}))
+			Expect(flag.Set("foo")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo}))
+		})
+
+		It("rejects repeated flags", func() {
+			foomodes := []FooModeTest{fmBaz}
+			flag := newFooModesTest(&foomodes, WithAccumulation(RejectRepeats))
+			_, err := parse(flag, "--modes=foo", "--modes=bar")
+			Expect(err).To(MatchError(ContainSubstring(
+				`invalid argument "bar" for "--modes" flag: must be specified only once`)))
+			var cerr *ConstraintError
+			Expect(errors.As(err, &cerr)).To(BeTrue())
+			Expect(cerr.Kind).To(Equal(RepeatConstraint))
+			Expect(cerr.Type).To(Equal("modes"))
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo}))
+		})
+
+		It("doesn't count failed Sets as occurrences", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes, WithAccumulation(RejectRepeats))
+			cmd, err := parse(flag, "--modes=fool")
+			Expect(err).To(HaveOccurred())
+			Expect(cmd.Flags().Lookup("modes").Changed).To(BeFalse())
+			Expect(flag.Set("foo")).To(Succeed())
+		})
+
+		It("resets to the default", func() {
+			foomodes := []FooModeTest{fmBaz}
+			flag := newFooModesTest(&foomodes, WithAccumulation(RejectRepeats))
+			cmd, err := parse(flag, "--modes=foo")
+			Expect(err).NotTo(HaveOccurred())
+
+			flag.Reset()
+			cmd.Flags().Lookup("modes").Changed = false
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz}))
+			Expect(cmd.Flags().Parse([]string{"--modes=bar"})).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBar}))
+			Expect(cmd.Flags().Lookup("modes").Changed).To(BeTrue())
+		})
+
+		It("resets scalars and bitmasks", func() {
+			foomode := fmBar
+			flag := New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive)
+			Expect(flag.Set("baz")).To(Succeed())
+			flag.Reset()
+			Expect(foomode).To(Equal(fmBar))
+
+			perm := permRead
+			bitmask := NewBitmask(&perm, "perm", permIdentifiersTest, EnumCaseInsensitive)
+			Expect(bitmask.Set("write")).To(Succeed())
+			Expect(bitmask.Set("exec")).To(Succeed())
+			bitmask.Reset()
+			Expect(perm).To(Equal(permRead))
+			Expect(bitmask.Set("write")).To(Succeed())
+			Expect(perm).To(Equal(permWrite))
+		})
+
+		It("panics on unknown accumulation policies", func() {
+			var foomodes []FooModeTest
+			Expect(func() { newFooModesTest(&foomodes, WithAccumulation(AccumulationPolicy(42))) }).
+				To(PanicWith(MatchRegexp(`unknown accumulation policy 42`)))
+		})
+
+	})
+
+})
@@ -0,0 +1,293 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EmptyElementPolicy specifies how slice enum flags handle empty elements,
+// such as in “foo,,bar”.
+type EmptyElementPolicy uint8
+
+// Empty element policies for slice enum flags; see [WithEmptyElements].
+//
+// RejectEmptyElements rejects empty elements with an [*InvalidValueError].
+// This is the default.
+//
+// IgnoreEmptyElements silently skips empty elements, so that “foo,,bar”
+// equals “foo,bar”, and an empty textual representation sets an empty slice.
+const (
+	RejectEmptyElements EmptyElementPolicy = iota
+	IgnoreEmptyElements
+)
+
+// WithSeparator sets the separator between the elements of slice enum flags,
+// such as “:”, “;”, or “|”, instead of the default “,”. The separator applies
+// to parsing as well as to the textual representation and completion.
+//
+// The slice enum flag value constructor panics if the separator is empty,
+// conflicts with edit operators or quoting, or if (unless quoting) any
+// identifier or meta keyword contains the separator.
+func WithSeparator(sep string) Option {
+	return func(o *options) {
+		o.separator = sep
+	}
+}
+
+// WithQuoting enables CSV-style quoting of slice enum flag elements, so that
+// identifiers containing the separator can be specified. Quoted elements are
+// enclosed in double quotes, with double quotes inside quoted elements
+// written twice, such as in “"foo,bar",baz”. Edit operators (see
+// [WithEditOperators]) precede the opening quote, such as in “+"foo,bar"”.
+func WithQuoting() Option {
+	return func(o *options) {
+		o.quoting = true
+	}
+}
+
+// WithParseableString renders the textual representation of slice enum flags
+// in a format that Set accepts, using the same separator and quoting, instead
+// of the default “[foo,bar]” format. This allows round-tripping default values
+// as well as serializing and replaying flag state. Set then accepts an empty
+// textual representation, setting an empty slice. As the textual
+// representation cannot fail, unmapped enum values are left out; use
+// [EnumSliceFlagValue.MarshalText] instead to detect unmapped enum values.
+func WithParseableString() Option {
+	return func(o *options) {
+		o.parseable = true
+	}
+}
+
+// WithEmptyElements sets the policy for handling empty slice enum flag
+// elements.
+//
+// The slice enum flag value constructor panics if the policy is unknown.
+func WithEmptyElements(policy EmptyElementPolicy) Option {
+	return func(o *options) {
+		o.emptyElements = policy
+	}
+}
+
+// listSplitter splits partial lists of enum identifiers during completion and
+// quotes completed identifiers.
+type listSplitter interface {
+	// tail returns the prefix of the specified partial list up to and
+	// including the last separator, as well as the (unquoted) non-empty
+	// elements in this prefix.
+	tail(s string) (prefix string, completes []string)
+	// quote returns the specified identifier, quoted if necessary.
+	quote(id string) string
+}
+
+// listElement is an (unquoted) element of a list of enum identifiers.
+type listElement struct {
+	text   string // unquoted element text
+	offset int    // byte offset of the element text inside the list
+}
+
+// listSeparator splits and joins lists of enum identifiers using a single
+// separator, optionally with CSV-style quoting. The zero listSeparator uses
+// the default “,” separator without quoting.
+type listSeparator struct {
+	sep       string
+	quoting   bool
+	operators bool // edit operators may precede opening quotes, as in “+"a,b"”.
+}
+
+// separator returns the separator, defaulting to “,”.
+func (l listSeparator) separator() string {
+	if l.sep == "" {
+		return ","
+	}
+	return l.sep
+}
+
+var _ listSplitter = listSeparator{}
+
+// split splits the specified list into its (unquoted) elements, keeping empty
+// elements. An edit operator preceding an opening quote is kept in front of
+// the unquoted element text. It returns an [*InvalidValueError] in case of
+// malformed quoting.
+func (l listSeparator) split(s string) ([]listElement, error) {
+	sep := l.separator()
+	elements := []listElement{}
+	pos := 0
+	for {
+		start := pos
+		text := ""
+		op := ""
+		if l.quoting && l.operators &&
+			(strings.HasPrefix(s[pos:], `+"`) || strings.HasPrefix(s[pos:], `-"`)) {
+			op = s[pos : pos+1]
+			pos++
+		}
+		if l.quoting && strings.HasPrefix(s[pos:], `"`) {
+			var b strings.Builder
+			b.WriteString(op)
+			pos++
+			for {
+				idx := strings.IndexByte(s[pos:], '"')
+				if idx < 0 {
+					return nil, l.malformed(s, start, len(elements),
+						fmt.Sprintf("unterminated quote in '%s'", s[start:]))
+				}
+				b.WriteString(s[pos : pos+idx])
+				pos += idx + 1
+				if !strings.HasPrefix(s[pos:], `"`) {
+					break
+				}
+				b.WriteByte('"')
+				pos++
+			}
+			if pos < len(s) && !strings.HasPrefix(s[pos:], sep) {
+				return nil, l.malformed(s, start, len(elements),
+					fmt.Sprintf("expected separator '%s' after quoted '%s'", sep, s[start:pos]))
+			}
+			text = b.String()
+			start++ // ...as if any edit operator immediately preceded the unquoted text.
+		} else {
+			idx := strings.Index(s[pos:], sep)
+			if idx < 0 {
+				idx = len(s) - pos
+			}
+			text = s[pos : pos+idx]
+			pos += idx
+		}
+		elements = append(elements, listElement{text: text, offset: start})
+		if pos >= len(s) {
+			return elements, nil
+		}
+		pos += len(sep)
+	}
+}
+
+// malformed returns an InvalidValueError for malformed quoting of the element
+// starting at the specified offset.
+func (l listSeparator) malformed(s string, offset int, index int, reason string) *InvalidValueError {
+	return &InvalidValueError{
+		Input:  s[offset:],
+		Index:  index,
+		Offset: offset,
+		reason: reason,
+	}
+}
+
+// join returns the specified identifiers joined by the separator, quoting
+// identifiers as necessary.
+func (l listSeparator) join(ids []string) string {
+	sep := l.separator()
+	quoted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		quoted = append(quoted, l.quote(id))
+	}
+	return strings.Join(quoted, sep)
+}
+
+// quote returns the specified identifier in double quotes if quoting is
+// enabled and the identifier contains the separator or double quotes;
+// otherwise, it returns the identifier unchanged.
+func (l listSeparator) quote(id string) string {
+	sep := l.separator()
+	if !l.quoting || (!strings.Contains(id, sep) && !strings.Contains(id, `"`)) {
+		return id
+	}
+	return `"` + strings.ReplaceAll(id, `"`, `""`) + `"`
+}
+
+// tail returns the prefix of the specified partial list up to and including
+// the last separator outside quotes, as well as the (unquoted) non-empty
+// elements in this prefix.
+func (l listSeparator) tail(s string) (prefix string, completes []string) {
+	sep := l.separator()
+	last := -1
+	quoted := false
+	for pos := 0; pos < len(s); pos++ {
+		switch {
+		case l.quoting && s[pos] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[pos:], sep):
+			last = pos + len(sep)
+			pos += len(sep) - 1
+		}
+	}
+	if last < 0 {
+		return "", []string{}
+	}
+	prefix = s[:last]
+	elements, _ := l.split(prefix[:last-len(sep)])
+	completes = []string{}
+	for _, element := range elements {
+		if element.text != "" {
+			completes = append(completes, element.text)
+		}
+	}
+	return prefix, completes
+}
+
+// anySeparator splits lists of enum identifiers at any of its separator
+// characters; it doesn't support quoting.
+type anySeparator string
+
+var _ listSplitter = anySeparator("")
+
+// tail returns the prefix of the specified partial list up to and including
+// the last separator, as well as the non-empty elements in this prefix.
+func (a anySeparator) tail(s string) (prefix string, completes []string) {
+	completes = []string{}
+	lastSep := strings.LastIndexAny(s, string(a))
+	if lastSep < 0 {
+		return "", completes
+	}
+	prefix = s[:lastSep+1] // ...Prof J. won't ever like this variable name
+	completes = strings.FieldsFunc(prefix, func(r rune) bool {
+		return strings.ContainsRune(string(a), r)
+	})
+	return prefix, completes
+}
+
+// quote returns the specified identifier unchanged.
+func (a anySeparator) quote(id string) string { return id }
+
+// validateListSyntax checks the separator, quoting, and empty element policy
+// in the specified options against the mapping and each other, returning an
+// error describing all problems found, if any.
+func validateListSyntax[E comparable](mapping EnumIdentifiers[E], o options) error {
+	problems := []string{}
+	sep := o.separator
+	switch {
+	case sep == "":
+		problems = append(problems, "separator must not be empty")
+	case o.quoting && strings.Contains(sep, `"`):
+		problems = append(problems, fmt.Sprintf("separator '%s' conflicts with quoting", sep))
+	case o.editOperators && strings.ContainsAny(sep, "+-"):
+		problems = append(problems, fmt.Sprintf("separator '%s' conflicts with edit operators", sep))
+	}
+	if o.emptyElements > IgnoreEmptyElements {
+		problems = append(problems, fmt.Sprintf("unknown empty element policy %d", o.emptyElements))
+	}
+	if sep == "" || o.quoting {
+		return problemsError(problems)
+	}
+	for _, keyword := range []metaKeyword{o.all, o.none} {
+		if strings.Contains(keyword.keyword, sep) {
+			problems = append(problems, fmt.Sprintf(
+				"keyword '%s' contains separator '%s'", keyword.keyword, sep))
+		}
+	}
+	return errors.Join(problemsError(problems), validateSeparator(mapping, sep))
+}
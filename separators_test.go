@@ -0,0 +1,186 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type sepTest int
+
+const (
+	sepA sepTest = iota + 1
+	sepB
+	sepC
+)
+
+var sepIdentifiersTest = EnumIdentifiers[sepTest]{
+	sepA: {"a,b"},
+	sepB: {`say "cheese"`},
+	sepC: {"c"},
+}
+
+var _ = Describe("slice separators", func() {
+
+	It("splits at the configured separator", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithSeparator("::"))
+		Expect(flag.Set("foo::baz")).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBaz}))
+		Expect(flag.String()).To(Equal("[foo::baz]"))
+
+		err := flag.Set("bar::fool")
+		Expect(err).To(HaveOccurred())
+		ierr := err.(*InvalidValueError)
+		Expect(ierr.Index).To(Equal(1))
+		Expect(ierr.Offset).To(Equal(5))
+	})
+
+	It("completes using the configured separator", func() {
+		var foomodes []FooModeTest
+		flag := newFooModesTest(&foomodes, WithSeparator(";"))
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, _ := completor(&cobra.Command{}, nil, "foo;")
+		Expect(completions).To(Equal([]string{"foo;bar", "foo;Bar", "foo;baz"}))
+		completions, _ = completor(&cobra.Command{}, nil, "foo,")
+		Expect(completions).To(Equal([]string{"foo", "bar", "Bar", "baz"}))
+	})
+
+	It("completes edits using the configured separator", func() {
+		foomodes := []FooModeTest{fmFoo}
+		flag := newFooModesTest(&foomodes, WithSeparator(":"), WithEditOperators())
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, _ := completor(&cobra.Command{}, nil, "+bar:-")
+		Expect(completions).To(Equal([]string{"+bar:-foo"}))
+	})
+
+	Context("empty elements", func() {
+
+		It("rejects empty elements by default", func() {
+			var foomodes []FooModeTest
+			flag := newFooModesTest(&foomodes)
+			err := flag.Set("foo,,bar")
+			Expect(err).To(HaveOccurred())
+			ierr := err.(*InvalidValueError)
+			Expect(ierr.Index).To(Equal(1))
+			Expect(ierr.Offset).To(Equal(4))
+			Expect(flag.Set("")).NotTo(Succeed())
+		})
+
+		It("ignores empty elements", func() {
+			foomodes := []FooModeTest{fmBaz}
+			flag := newFooModesTest(&foomodes, WithEmptyElements(IgnoreEmptyElements))
+			Expect(flag.Set(",foo,,bar,")).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar}))
+
+			foomodes = []FooModeTest{fmBaz}
+			flag = newFooModesTest(&foomodes, WithEmptyElements(IgnoreEmptyElements))
+			Expect(flag.Set("")).To(Succeed())
+			Expect(foomodes).To(BeEmpty())
+		})
+
+	})
+
+	Context("quoting", func() {
+
+		newSepFlag := func(seps *[]sepTest, opts ...Option) *EnumSliceFlagValue[sepTest] {
+			return NewSlice(seps, "seps", sepIdentifiersTest, EnumCaseSensitive,
+				append([]Option{WithOrder(sepA, sepB, sepC), WithQuoting()}, opts...)...)
+		}
+
+		It("parses quoted elements", func() {
+			var seps []sepTest
+			flag := newSepFlag(&seps)
+			Expect(flag.Set(`"a,b",c,"say ""cheese"""`)).To(Succeed())
+			Expect(seps).To(Equal([]sepTest{sepA, sepC, sepB}))
+			Expect(flag.String()).To(Equal(`["a,b",c,"say ""cheese"""]`))
+		})
+
+		It("reports offsets of quoted elements", func() {
+			var seps []sepTest
+			flag := newSepFlag(&seps)
+			err := flag.Set(`c,"a,c"`)
+			Expect(err).To(HaveOccurred())
+			ierr := err.(*InvalidValueError)
+			Expect(ierr.Input).To(Equal("a,c"))
+			Expect(ierr.Index).To(Equal(1))
+			Expect(ierr.Offset).To(Equal(3))
+		})
+
+		DescribeTable("rejects malformed quoting",
+			func(val string, expected string, index int, offset int) {
+				var seps []sepTest
+				flag := newSepFlag(&seps)
+				err := flag.Set(val)
+				Expect(err).To(MatchError(expected))
+				ierr := err.(*InvalidValueError)
+				Expect(ierr.Type).To(Equal("seps"))
+				Expect(ierr.Index).To(Equal(index))
+				Expect(ierr.Offset).To(Equal(offset))
+			},
+			Entry(nil, `c,"a,b`, `unterminated quote in '"a,b'`, 1, 2),
+			Entry(nil, `"a,b"c`, `expected separator ',' after quoted '"a,b"'`, 0, 0),
+		)
+
+		It("completes quoted identifiers", func() {
+			var seps []sepTest
+			flag := newSepFlag(&seps)
+			completor := flag.value.NewCompletor(flag.names, nil)
+			completions, _ := completor(&cobra.Command{}, nil, `"a,b",`)
+			Expect(completions).To(Equal([]string{`"a,b","say ""cheese"""`, `"a,b",c`}))
+		})
+
+		It("accepts completed edits of quoted identifiers", func() {
+			seps := []sepTest{sepC}
+			flag := newSepFlag(&seps, WithEditOperators())
+			completor := flag.value.NewCompletor(flag.names, nil)
+			completions, _ := completor(&cobra.Command{}, nil, "+")
+			Expect(completions).To(ConsistOf(`+"a,b"`, `+"say ""cheese"""`))
+			Expect(flag.Set(`+"a,b",-c`)).To(Succeed())
+			Expect(seps).To(ConsistOf(sepA))
+
+			err := flag.Set(`+"a,c"`)
+			Expect(err).To(HaveOccurred())
+			ierr := err.(*InvalidValueError)
+			Expect(ierr.Input).To(Equal("a,c"))
+			Expect(ierr.Offset).To(Equal(2))
+		})
+
+		It("panics on identifiers with separators unless quoting", func() {
+			var seps []sepTest
+			Expect(func() {
+				NewSlice(&seps, "seps", sepIdentifiersTest, EnumCaseSensitive)
+			}).To(PanicWith(MatchRegexp(`identifier 'a,b' of enum value 1 contains separator ','`)))
+		})
+
+	})
+
+	DescribeTable("panics on invalid separators",
+		func(expected string, opts ...Option) {
+			var foomodes []FooModeTest
+			Expect(func() { newFooModesTest(&foomodes, opts...) }).To(PanicWith(MatchRegexp(expected)))
+		},
+		Entry(nil, `separator must not be empty`, WithSeparator("")),
+		Entry(nil, `separator '"' conflicts with quoting`, WithSeparator(`"`), WithQuoting()),
+		Entry(nil, `separator '-' conflicts with edit operators`, WithSeparator("-"), WithEditOperators()),
+		Entry(nil, `keyword 'a;b' contains separator ';'`, WithSeparator(";"), WithAllKeyword("a;b", "")),
+		Entry(nil, `identifier 'bar' of enum value 2 contains separator 'a'`, WithSeparator("a")),
+		Entry(nil, `unknown empty element policy 42`, WithEmptyElements(EmptyElementPolicy(42))),
+	)
+
+})
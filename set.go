@@ -0,0 +1,174 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// EnumSet is a set of enum values, as bound to set enum flags using
+// [NewSet]. Iterating an EnumSet bound to a set enum flag yields its enum
+// values in mapping order (see [WithOrder]), followed by any other enum
+// values sorted by their default textual representations. The zero EnumSet
+// is an empty set ready to use.
+type EnumSet[E comparable] struct {
+	members map[E]struct{}
+	order   []E // mapping order for iteration, if known.
+}
+
+// NewEnumSet returns a new EnumSet with the specified enum values.
+func NewEnumSet[E comparable](enumvals ...E) EnumSet[E] {
+	var s EnumSet[E]
+	s.Add(enumvals...)
+	return s
+}
+
+// Contains returns true if the set contains the specified enum value.
+func (s EnumSet[E]) Contains(enumval E) bool {
+	_, ok := s.members[enumval]
+	return ok
+}
+
+// Len returns the number of enum values in the set.
+func (s EnumSet[E]) Len() int { return len(s.members) }
+
+// Add adds the specified enum values to the set.
+func (s *EnumSet[E]) Add(enumvals ...E) {
+	if s.members == nil {
+		s.members = make(map[E]struct{}, len(enumvals))
+	}
+	for _, enumval := range enumvals {
+		s.members[enumval] = struct{}{}
+	}
+}
+
+// Remove removes the specified enum values from the set.
+func (s *EnumSet[E]) Remove(enumvals ...E) {
+	for _, enumval := range enumvals {
+		delete(s.members, enumval)
+	}
+}
+
+// Union returns a new set with the enum values of both sets.
+func (s EnumSet[E]) Union(other EnumSet[E]) EnumSet[E] {
+	union := EnumSet[E]{
+		members: make(map[E]struct{}, len(s.members)+len(other.members)),
+		order:   s.order,
+	}
+	if union.order == nil {
+		union.order = other.order
+	}
+	for enumval := range s.members {
+		union.members[enumval] = struct{}{}
+	}
+	for enumval := range other.members {
+		union.members[enumval] = struct{}{}
+	}
+	return union
+}
+
+// All returns an iterator over the enum values in the set, in mapping order
+// if known.
+func (s EnumSet[E]) All() iter.Seq[E] {
+	return slices.Values(s.Values())
+}
+
+// Values returns the enum values in the set as a slice, in mapping order if
+// known.
+func (s EnumSet[E]) Values() []E {
+	enumvals := make([]E, 0, len(s.members))
+	for _, enumval := range s.order {
+		if s.Contains(enumval) {
+			enumvals = append(enumvals, enumval)
+		}
+	}
+	if len(enumvals) == len(s.members) {
+		return enumvals
+	}
+	rest := make([]E, 0, len(s.members)-len(enumvals))
+	for enumval := range s.members {
+		if !slices.Contains(s.order, enumval) {
+			rest = append(rest, enumval)
+		}
+	}
+	slices.SortFunc(rest, func(a, b E) int {
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+	return append(enumvals, rest...)
+}
+
+// enumSet represents a set of enumeration values that can be retrieved, set,
+// and stringified. It delegates parsing and completion to a slice enum value
+// working on a shadow slice of the set's enum values.
+type enumSet[E comparable] struct {
+	v      *EnumSet[E]
+	shadow []E
+	slice  *enumSlice[E]
+	order  []E // mapping order.
+}
+
+// sync updates the shadow slice from the set, picking up any changes made
+// directly to the set.
+func (s *enumSet[E]) sync() {
+	s.v.order = s.order
+	s.shadow = s.v.Values()
+}
+
+// Get returns the set of enum values.
+func (s *enumSet[E]) Get() any { return *s.v }
+
+// Reset restores the default enum values and forgets about any previous Set.
+func (s *enumSet[E]) Reset() {
+	s.slice.Reset()
+	s.v.members = nil
+	s.v.Add(s.shadow...)
+}
+
+// Set or merge one or more enum values, the same as for slice enum values.
+func (s *enumSet[E]) Set(val string, names enumMapper[E]) error {
+	s.sync()
+	if err := s.slice.Set(val, names); err != nil {
+		return err
+	}
+	s.v.members = nil
+	s.v.Add(s.shadow...)
+	return nil
+}
+
+// String returns the textual representation of the set of enum values, in
+// mapping order.
+func (s *enumSet[E]) String(names enumMapper[E]) string {
+	s.sync()
+	return s.slice.String(names)
+}
+
+// quote returns the specified identifier quoted the same as for slice enum
+// values.
+func (s *enumSet[E]) quote(id string) string { return s.slice.quote(id) }
+
+// NewCompletor returns a cobra Completor that completes enum flag values the
+// same as for slice enum values.
+func (s *enumSet[E]) NewCompletor(names enumMapper[E], help Help[E]) Completor {
+	complete := s.slice.NewCompletor(names, help)
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		s.sync()
+		return complete(cmd, args, toComplete)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("enum sets", func() {
+
+	Context("EnumSet", func() {
+
+		It("adds, removes, and contains", func() {
+			var s EnumSet[FooModeTest]
+			Expect(s.Contains(fmFoo)).To(BeFalse())
+			Expect(s.Len()).To(BeZero())
+			s.Remove(fmFoo)
+			s.Add(fmFoo, fmBar, fmFoo)
+			Expect(s.Len()).To(Equal(2))
+			Expect(s.Contains(fmFoo)).To(BeTrue())
+			s.Remove(fmFoo)
+			Expect(s.Contains(fmFoo)).To(BeFalse())
+			Expect(s.Values()).To(Equal([]FooModeTest{fmBar}))
+		})
+
+		It("unites", func() {
+			a := NewEnumSet(fmFoo)
+			b := NewEnumSet(fmBar, fmFoo)
+			u := a.Union(b)
+			Expect(u.Values()).To(Equal([]FooModeTest{fmFoo, fmBar}))
+			Expect(a.Len()).To(Equal(1))
+		})
+
+		It("iterates without mapping order stably", func() {
+			s := NewEnumSet(fmBaz, fmFoo, fmBar)
+			Expect(slices.Collect(s.All())).To(Equal([]FooModeTest{fmFoo, fmBar, fmBaz}))
+		})
+
+	})
+
+	It("sets and merges", func() {
+		foomodes := NewEnumSet(fmBar)
+		flag := NewSet(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithOrder(fmBaz, fmBar, fmFoo))
+		Expect(flag.String()).To(Equal("[bar]"))
+		Expect(flag.Set("foo,FOO")).To(Succeed())
+		Expect(foomodes.Contains(fmBar)).To(BeFalse())
+		Expect(flag.Set("baz,foo")).To(Succeed())
+		Expect(slices.Collect(foomodes.All())).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		Expect(flag.String()).To(Equal("[baz,foo]"))
+		Expect(flag.GetSetValue().Values()).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+
+		foomodes.Add(fmBar)
+		Expect(flag.String()).To(Equal("[baz,bar,foo]"))
+
+		flag.Reset()
+		Expect(foomodes.Values()).To(Equal([]FooModeTest{fmBar}))
+	})
+
+	It("shares slice options", func() {
+		foomodes := NewEnumSet(fmBar)
+		flag := NewSet(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithEditOperators(), WithMaxCount(2), WithSeparator(":"))
+		Expect(flag.Set("+foo:-bar:+baz")).To(Succeed())
+		Expect(foomodes.Values()).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		Expect(flag.Set("+bar")).To(MatchError("allows at most 2 value(s), but got 3"))
+		Expect(flag.String()).To(Equal("[baz:foo]"))
+	})
+
+	It("rejects duplicates when asked to", func() {
+		var foomodes EnumSet[FooModeTest]
+		flag := NewSet(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithDuplicates(RejectDuplicates))
+		Expect(flag.Set("foo,foo")).To(MatchError("'foo' must not be specified more than once"))
+		Expect(foomodes.Len()).To(BeZero())
+	})
+
+	It("completes", func() {
+		foomodes := NewEnumSet(fmBar)
+		flag := NewSet(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithEditOperators(), WithOrder(fmFoo, fmBar, fmBaz))
+		completor := flag.value.NewCompletor(flag.names, nil)
+		completions, _ := completor(&cobra.Command{}, nil, "-")
+		Expect(completions).To(Equal([]string{"-bar", "-Bar"}))
+		foomodes.Add(fmBaz)
+		completions, _ = completor(&cobra.Command{}, nil, "-")
+		Expect(completions).To(Equal([]string{"-bar", "-Bar", "-baz"}))
+	})
+
+	It("panics on invalid parameters", func() {
+		Expect(func() { NewSet[FooModeTest](nil, "modes", FooModeIdentifiersTest, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewSet requires flag to be a non-nil pointer to an enum set`)))
+		foomodes := NewEnumSet[FooModeTest](42)
+		Expect(func() { NewSet(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive) }).
+			To(PanicWith(MatchRegexp(`NewSet requires flag to reference mapped enum values only, but 42 isn't mapped`)))
+	})
+
+})
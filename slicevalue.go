@@ -0,0 +1,74 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// EnumSliceFlagValue wraps a user-defined enum slice type value, as returned
+// by [NewSlice]. In addition to the [github.com/spf13/pflag.Value] interface,
+// it implements the [github.com/spf13/pflag.SliceValue] interface, so that
+// tools can handle slice enum flags generically, using canonical enum names.
+type EnumSliceFlagValue[E comparable] struct {
+	EnumFlagValue[E]
+	slice *enumSlice[E]
+}
+
+var (
+	_ pflag.SliceValue       = (*EnumSliceFlagValue[int])(nil)
+	_ encoding.TextMarshaler = (*EnumSliceFlagValue[int])(nil)
+)
+
+// Append adds the enum value corresponding to the specified single identifier.
+// Append doesn't split its argument into multiple elements, and neither
+// supports edit operators nor meta keywords. Contrary to Set, Append never
+// replaces any default enum values. If the identifier doesn't match any of the
+// defined ones, an [*InvalidValueError] is returned instead. If the enum
+// values would violate any constraints, a [*ConstraintError] is returned
+// instead.
+func (e *EnumSliceFlagValue[E]) Append(val string) error {
+	return e.typed(e.slice.Append(val, e.names))
+}
+
+// Replace replaces all enum values with the enum values corresponding to the
+// specified identifiers, one identifier per element. Either all identifiers
+// are valid and the enum values don't violate any constraints, or the enum
+// values aren't changed and an [*InvalidValueError] or [*ConstraintError] is
+// returned.
+func (e *EnumSliceFlagValue[E]) Replace(vals []string) error {
+	return e.typed(e.slice.Replace(vals, e.names))
+}
+
+// GetSlice returns the canonical names of the enum values, in the order as
+// returned by [EnumFlagValue.GetSliceValue].
+func (e *EnumSliceFlagValue[E]) GetSlice() []string {
+	return e.slice.GetSlice(e.names)
+}
+
+// MarshalText returns the textual representation of the enum values in a
+// format that Set accepts, using the configured separator and quoting (see
+// [WithSeparator] and [WithQuoting]), regardless of [WithParseableString]. If
+// any enum value isn't mapped, MarshalText returns an error instead.
+func (e *EnumSliceFlagValue[E]) MarshalText() ([]byte, error) {
+	text, err := e.slice.Text(e.names, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %s: %w", e.enumtype, err)
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pflag.SliceValue", func() {
+
+	It("is implemented by slice flags only", func() {
+		foomode := fmFoo
+		var scalar pflag.Value = New(&foomode, "mode", FooModeIdentifiersTest, EnumCaseInsensitive)
+		_, ok := scalar.(pflag.SliceValue)
+		Expect(ok).To(BeFalse())
+
+		var foomodes []FooModeTest
+		var slice pflag.Value = NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+		_, ok = slice.(pflag.SliceValue)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("appends enum values", func() {
+		foomodes := []FooModeTest{fmBar}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+		Expect(flag.Append("BAZ")).To(Succeed())
+		Expect(flag.Append("bar")).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmBar, fmBaz}))
+		Expect(flag.GetSlice()).To(Equal([]string{"bar", "baz"}))
+
+		err := flag.Append("foo,bar")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*InvalidValueError).Type).To(Equal("modes"))
+		Expect(foomodes).To(Equal([]FooModeTest{fmBar, fmBaz}))
+	})
+
+	It("appends subject to policies and constraints", func() {
+		foomodes := []FooModeTest{fmBar}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithDuplicates(RejectDuplicates), WithMaxCount(2), WithOrderPolicy(SortedOrder))
+		Expect(flag.Append("bar")).To(MatchError("'bar' must not be specified more than once"))
+		Expect(flag.Append("foo")).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmFoo, fmBar}))
+		err := flag.Append("baz")
+		Expect(err).To(MatchError("allows at most 2 value(s), but got 3"))
+		Expect(err.(*ConstraintError).Type).To(Equal("modes"))
+	})
+
+	It("replaces enum values", func() {
+		foomodes := []FooModeTest{fmBar}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithMinCount(1))
+		Expect(flag.Replace([]string{"baz", "Foo"})).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		Expect(flag.GetSlice()).To(Equal([]string{"baz", "foo"}))
+
+		err := flag.Replace([]string{"bar", "fool"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*InvalidValueError).Index).To(Equal(1))
+		Expect(flag.Replace([]string{})).To(MatchError("requires at least 1 value(s), but got 0"))
+		Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+	})
+
+	It("doesn't count appending as setting", func() {
+		foomodes := []FooModeTest{fmBar}
+		flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+			WithAccumulation(RejectRepeats))
+		Expect(flag.Append("foo")).To(Succeed())
+		Expect(flag.Set("baz")).To(Succeed())
+		Expect(foomodes).To(Equal([]FooModeTest{fmBaz}))
+		Expect(flag.Set("foo")).NotTo(Succeed())
+	})
+
+	Context("parseable textual representation", func() {
+
+		It("round-trips default values", func() {
+			foomodes := []FooModeTest{fmBaz, fmFoo}
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			fs.Var(NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithParseableString(), WithAccumulation(ReplaceRepeats)), "modes", "")
+			f := fs.Lookup("modes")
+			Expect(f.DefValue).To(Equal("baz,foo"))
+			Expect(fs.Parse([]string{"--modes=bar"})).To(Succeed())
+			Expect(f.Value.Set(f.DefValue)).To(Succeed())
+			Expect(foomodes).To(Equal([]FooModeTest{fmBaz, fmFoo}))
+		})
+
+		It("round-trips empty slices", func() {
+			var foomodes []FooModeTest
+			flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithParseableString())
+			Expect(flag.String()).To(BeEmpty())
+			Expect(flag.Set("foo")).To(Succeed())
+			Expect(flag.Set("")).To(Succeed())
+			Expect(foomodes).To(BeEmpty())
+		})
+
+		It("uses the separator and quoting", func() {
+			seps := []sepTest{sepC, sepA, sepB}
+			flag := NewSlice(&seps, "seps", sepIdentifiersTest, EnumCaseSensitive,
+				WithQuoting(), WithSeparator(";"), WithParseableString())
+			Expect(flag.String()).To(Equal(`c;a,b;"say ""cheese"""`))
+			seps = nil
+			Expect(flag.Set(`c;a,b;"say ""cheese"""`)).To(Succeed())
+			Expect(seps).To(Equal([]sepTest{sepC, sepA, sepB}))
+		})
+
+		It("handles unmapped enum values explicitly", func() {
+			foomodes := []FooModeTest{fmBar}
+			flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive,
+				WithParseableString())
+			foomodes = append(foomodes, 42)
+			Expect(flag.String()).To(Equal("bar"))
+			_, err := flag.MarshalText()
+			Expect(err).To(MatchError("cannot marshal modes: enum value 42 isn't mapped"))
+
+			foomodes = []FooModeTest{fmBar, fmFoo}
+			Expect(flag.MarshalText()).To(Equal([]byte("bar,foo")))
+		})
+
+		It("marshals without parseable String", func() {
+			foomodes := []FooModeTest{fmBar, fmFoo}
+			flag := NewSlice(&foomodes, "modes", FooModeIdentifiersTest, EnumCaseInsensitive)
+			Expect(flag.String()).To(Equal("[bar,foo]"))
+			Expect(flag.MarshalText()).To(Equal([]byte("bar,foo")))
+		})
+
+	})
+
+})
@@ -0,0 +1,119 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSuggestions limits the number of “did you mean...?” suggestions.
+const maxSuggestions = 2
+
+// suggestion is an identifier that is close to some invalid input.
+type suggestion struct {
+	id       string
+	pos      int // position of id in the identifiers of its enum value
+	distance int
+}
+
+// suggest returns up to maxSuggestions identifiers that are closest to the
+// specified input, ordered by increasing distance. Distances are calculated
+// between the normalized forms of input and identifiers. Only one advertised
+// identifier per enum value is suggested, and only identifiers within a
+// distance threshold relative to the length of the input are considered at
+// all.
+func suggest[E comparable](input string, indices []matcherIndex[E]) []string {
+	best := map[E]suggestion{}
+	for _, mi := range indices {
+		input := mi.matcher.Normalize(input)
+		inputlen := utf8.RuneCountInString(input)
+		threshold := max(1, inputlen/3)
+		for _, k := range mi.keys {
+			if !k.listed {
+				continue
+			}
+			// Skip identifiers that are already too different in length to
+			// be within the threshold distance.
+			if abs(utf8.RuneCountInString(k.key)-inputlen) > threshold {
+				continue
+			}
+			d := distance(input, k.key)
+			if d > threshold {
+				continue
+			}
+			if b, ok := best[k.enumval]; ok &&
+				(b.distance < d || (b.distance == d && b.pos < k.pos)) {
+				continue
+			}
+			best[k.enumval] = suggestion{id: k.id, pos: k.pos, distance: d}
+		}
+	}
+	candidates := make([]suggestion, 0, len(best))
+	for _, candidate := range best {
+		candidates = append(candidates, candidate)
+	}
+	slices.SortFunc(candidates, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.id, b.id)
+	})
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, candidate := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, candidate.id)
+	}
+	return suggestions
+}
+
+// distance returns the edit distance between the strings a and b in terms of
+// the minimum number of rune insertions, deletions, substitutions, and
+// transpositions of adjacent runes (“optimal string alignment distance”)
+// required to turn a into b. Counting transpositions as single edits makes
+// typical typos, such as “jsno” instead of “json”, come out as close.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// We only need to keep the last three rows of the distance matrix in
+	// order to handle transpositions.
+	rows := make([]int, 3*(len(rb)+1))
+	prevprev, prev, curr := rows[:len(rb)+1], rows[len(rb)+1:2*(len(rb)+1)], rows[2*(len(rb)+1):]
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevprev[j-2]+1)
+			}
+		}
+		prevprev, prev, curr = prev, curr, prevprev
+	}
+	return prev[len(rb)]
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("suggestions", func() {
+
+	DescribeTable("calculates edit distances",
+		func(a, b string, expected int) {
+			Expect(distance(a, b)).To(Equal(expected))
+			Expect(distance(b, a)).To(Equal(expected))
+		},
+		Entry(nil, "", "", 0),
+		Entry(nil, "", "foo", 3),
+		Entry(nil, "foo", "foo", 0),
+		Entry(nil, "foo", "fool", 1),
+		Entry(nil, "foo", "boo", 1),
+		Entry(nil, "jsno", "json", 1),
+		Entry(nil, "yaml", "json", 4),
+		Entry(nil, "møø", "moo", 2),
+		Entry(nil, "kitten", "sitting", 3),
+	)
+
+	FormatIdentifiersTest := map[int][]string{
+		1: {"json", "JSON"},
+		2: {"jsonl"},
+		3: {"yaml", "yml"},
+		4: {"xml"},
+		5: {"toml"},
+	}
+
+	DescribeTable("suggests closest identifiers",
+		func(input string, sensitivity EnumCaseSensitivity, expected []string) {
+			m := newEnumMapper(FormatIdentifiersTest, sensitivity)
+			Expect(suggest(input, m.indices)).To(Equal(expected))
+		},
+		Entry(nil, "jsno", EnumCaseInsensitive, []string{"json"}),
+		Entry(nil, "JSNO", EnumCaseSensitive, []string{"JSON"}),
+		Entry(nil, "jsonn", EnumCaseInsensitive, []string{"json", "jsonl"}),
+		Entry(nil, "yanl", EnumCaseInsensitive, []string{"yaml"}),
+		Entry(nil, "tml", EnumCaseInsensitive, []string{"toml", "xml"}),
+		Entry(nil, "csv", EnumCaseInsensitive, []string{}),
+	)
+
+})
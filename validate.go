@@ -0,0 +1,207 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Validate checks the specified enum identifiers mapping for problems that
+// would make parsing textual enum values ambiguous or impossible, returning
+// nil if there aren't any problems. Otherwise, it returns an error describing
+// all problems found. Validate reports:
+//   - enum values without any identifiers,
+//   - empty identifiers,
+//   - the same identifier assigned to different enum values, taking the
+//     specified Matcher (such as an [EnumCaseSensitivity]) into account.
+//
+// Please note that identifiers assigned to the same enum value multiple times
+// (or differing only in case) aren't a problem, as these are unambiguous.
+//
+// The enum flag value constructors, such as [New] and [NewSlice], validate
+// their mappings and panic in case of problems. Additionally, [NewSlice]
+// rejects identifiers containing its separator (see [WithSeparator]), as these
+// cannot be parsed as slice elements, unless quoting is enabled (see
+// [WithQuoting]).
+func Validate[E comparable](mapping EnumIdentifiers[E], matcher Matcher) error {
+	return validate(mapping, matcher, options{})
+}
+
+// validate checks the mapping as Validate does, but additionally takes the
+// per-identifier Matcher overrides into account.
+func validate[E comparable](mapping EnumIdentifiers[E], matcher Matcher, o options) error {
+	if matcher == nil {
+		return errors.New("matcher must not be nil")
+	}
+	// Identifiers only clash if they are normalized by the same Matcher, so
+	// we key the claims by the index of the Matcher as well as the normalized
+	// identifier.
+	type matcherKey struct {
+		idx int
+		key string
+	}
+	matchers := make([]Matcher, 0, len(o.overrides)+1)
+	problems := []string{}
+	for _, override := range o.overrides {
+		if override.matcher == nil {
+			problems = append(problems, fmt.Sprintf(
+				"identifiers %s have a nil matcher", quoted(override.ids)))
+		}
+		matchers = append(matchers, override.matcher)
+	}
+	matchers = append(matchers, matcher)
+	if o.numericCodes && !isIntegerEnum[E]() {
+		var zero E
+		problems = append(problems, fmt.Sprintf(
+			"numeric codes require an integer enum type, but %T isn't", zero))
+	}
+	for enumval := range o.deprecatedValues {
+		if problem := checkEnumValue("deprecated", enumval, mapping); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for _, enumval := range o.hiddenValues {
+		if problem := checkEnumValue("hidden", enumval, mapping); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for _, enumval := range o.order {
+		if problem := checkEnumValue("ordered", enumval, mapping); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if o.bare != nil {
+		if problem := checkEnumValue("bare", o.bare, mapping); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	matcherOf := o.matcherIndices()
+	mapped := map[string]struct{}{}
+	claims := map[matcherKey][]string{}       // normalized identifier to "'id' (value)" claims
+	owners := map[matcherKey]map[E]struct{}{} // normalized identifier to its enum values
+	for enumval, ids := range mapping {
+		if len(ids) == 0 {
+			problems = append(problems, fmt.Sprintf(
+				"enum value %v has no identifiers", enumval))
+			continue
+		}
+		for _, id := range ids {
+			if id == "" {
+				problems = append(problems, fmt.Sprintf(
+					"enum value %v has an empty identifier", enumval))
+				continue
+			}
+			mapped[id] = struct{}{}
+			if o.editOperators && (id[0] == '+' || id[0] == '-') {
+				problems = append(problems, fmt.Sprintf(
+					"identifier '%s' of enum value %v starts with an edit operator", id, enumval))
+			}
+			for _, keyword := range []string{o.all.keyword, o.none.keyword} {
+				if keyword != "" && matcher.Normalize(id) == matcher.Normalize(keyword) {
+					problems = append(problems, fmt.Sprintf(
+						"identifier '%s' of enum value %v clashes with meta keyword '%s'",
+						id, enumval, keyword))
+				}
+			}
+			idx, ok := matcherOf[id]
+			if !ok {
+				idx = len(matchers) - 1
+			}
+			if matchers[idx] == nil {
+				continue
+			}
+			key := matcherKey{idx: idx, key: matchers[idx].Normalize(id)}
+			if owners[key] == nil {
+				owners[key] = map[E]struct{}{}
+			}
+			owners[key][enumval] = struct{}{}
+			claims[key] = append(claims[key], fmt.Sprintf("'%s' (%v)", id, enumval))
+		}
+	}
+	for id := range matcherOf {
+		if _, ok := mapped[id]; !ok {
+			problems = append(problems, fmt.Sprintf(
+				"identifier '%s' has a matcher but isn't mapped", id))
+		}
+	}
+	for _, id := range o.hiddenIds {
+		if _, ok := mapped[id]; !ok {
+			problems = append(problems, fmt.Sprintf(
+				"hidden identifier '%s' isn't mapped", id))
+		}
+	}
+	for id := range o.deprecatedIds {
+		if _, ok := mapped[id]; !ok {
+			problems = append(problems, fmt.Sprintf(
+				"deprecated identifier '%s' isn't mapped", id))
+		}
+	}
+	for key, values := range owners {
+		if len(values) < 2 {
+			continue
+		}
+		slices.Sort(claims[key])
+		problems = append(problems, fmt.Sprintf(
+			"identifier '%s' is assigned to different enum values: %s",
+			key.key, strings.Join(claims[key], ", ")))
+	}
+	return problemsError(problems)
+}
+
+// checkEnumValue returns a description of the problem if the specified enum
+// value of the given kind, such as “deprecated”, either isn't of enum type E or
+// isn't mapped. Otherwise, it returns an empty string.
+func checkEnumValue[E comparable](kind string, enumval any, mapping EnumIdentifiers[E]) string {
+	e, ok := enumval.(E)
+	if !ok {
+		var zero E
+		return fmt.Sprintf("%s enum value %v is of type %T instead of %T", kind, enumval, enumval, zero)
+	}
+	if _, ok := mapping[e]; !ok {
+		return fmt.Sprintf("%s enum value %v isn't mapped", kind, e)
+	}
+	return ""
+}
+
+// validateSeparator checks that none of the identifiers in the specified
+// mapping contains the separator, returning an error describing all
+// offending identifiers otherwise.
+func validateSeparator[E comparable](mapping EnumIdentifiers[E], sep string) error {
+	problems := []string{}
+	for enumval, ids := range mapping {
+		for _, id := range ids {
+			if strings.Contains(id, sep) {
+				problems = append(problems, fmt.Sprintf(
+					"identifier '%s' of enum value %v contains separator '%s'",
+					id, enumval, sep))
+			}
+		}
+	}
+	return problemsError(problems)
+}
+
+// problemsError returns nil if there are no problems, otherwise an error
+// joining the problems in a stable order.
+func problemsError(problems []string) error {
+	slices.Sort(problems)
+	errs := make([]error, 0, len(problems))
+	for _, problem := range problems {
+		errs = append(errs, errors.New(problem))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mapping validation", func() {
+
+	DescribeTable("accepts valid mappings",
+		func(mapping EnumIdentifiers[FooModeTest], sensitivity EnumCaseSensitivity) {
+			Expect(Validate(mapping, sensitivity)).To(Succeed())
+		},
+		Entry("test mapping", FooModeIdentifiersTest, EnumCaseSensitive),
+		Entry("test mapping/i", FooModeIdentifiersTest, EnumCaseInsensitive),
+		Entry("empty mapping", EnumIdentifiers[FooModeTest]{}, EnumCaseSensitive),
+		Entry("case-only differences", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: {"Foo"},
+		}, EnumCaseSensitive),
+		Entry("repeated identifiers of the same value", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo", "foo"},
+		}, EnumCaseSensitive),
+	)
+
+	DescribeTable("reports problems",
+		func(mapping EnumIdentifiers[FooModeTest], sensitivity EnumCaseSensitivity, expected string) {
+			Expect(Validate(mapping, sensitivity)).To(MatchError(expected))
+		},
+		Entry("missing identifiers", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: nil,
+		}, EnumCaseSensitive, "enum value 2 has no identifiers"),
+		Entry("empty identifier", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo", ""},
+		}, EnumCaseSensitive, "enum value 1 has an empty identifier"),
+		Entry("duplicate identifiers", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: {"bar", "foo"},
+		}, EnumCaseSensitive, "identifier 'foo' is assigned to different enum values: 'foo' (1), 'foo' (2)"),
+		Entry("duplicate identifiers/i", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo"},
+			fmBar: {"Foo"},
+		}, EnumCaseInsensitive, "identifier 'foo' is assigned to different enum values: 'Foo' (2), 'foo' (1)"),
+		Entry("multiple problems", EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo", ""},
+			fmBar: {},
+		}, EnumCaseSensitive, "enum value 1 has an empty identifier\nenum value 2 has no identifiers"),
+	)
+
+	It("reports separators in identifiers", func() {
+		Expect(validateSeparator(FooModeIdentifiersTest, ",")).To(Succeed())
+		Expect(validateSeparator(EnumIdentifiers[FooModeTest]{
+			fmFoo: {"foo", "f,o,o"},
+		}, ",")).To(MatchError("identifier 'f,o,o' of enum value 1 contains separator ','"))
+	})
+
+})
@@ -0,0 +1,183 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Integer is satisfied by all integer-kinded enum types, as required for
+// bitmask enum flags.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// bitmaskSeparators separate the individual enum identifiers when setting a
+// bitmask enum value.
+const bitmaskSeparators = ",|"
+
+// enumBitmask represents a bitmask enumeration value that can be retrieved,
+// set, and stringified, combining multiple enum values into a single one.
+type enumBitmask[E Integer] struct {
+	v      *E
+	def    E    // default bitmask enum value for resetting.
+	mapped E    // all bits covered by mapped enum values.
+	merge  bool // replace the complete bitmask or merge values?
+}
+
+// Get returns the bitmask enum value.
+func (b *enumBitmask[E]) Get() any { return *b.v }
+
+// Reset restores the default bitmask enum value and forgets about any
+// previous Set.
+func (b *enumBitmask[E]) Reset() {
+	*b.v = b.def
+	b.merge = false
+}
+
+// Set or merge the bitmask enum value from the passed textual representation
+// of one or more enum values, separated by either “,” or “|”. If any of the
+// specified textual representations doesn't match any of the defined ones, an
+// error is returned instead and the value isn't changed. The first call to Set
+// will always clear any previous default value. All subsequent calls to Set
+// will merge the specified enum values with the current bitmask enum value.
+//
+// An empty textual representation clears the bitmask enum value. Additionally,
+// remaining bits in hexadecimal notation, such as “0x10”, are accepted as
+// long as these bits are covered by mapped enum values, so that the textual
+// representation returned by String can always be fed back into Set.
+func (b *enumBitmask[E]) Set(val string, names enumMapper[E]) error {
+	if val == "" {
+		*b.v = 0
+		b.merge = true
+		return nil
+	}
+	var bitmask E
+	warnings := []string{}
+	offset := 0
+	for idx, id := range splitAny(val, bitmaskSeparators) {
+		enumval, warning, err := names.Parse(id)
+		if err != nil {
+			if bits, ok := b.bits(id); ok {
+				bitmask |= bits
+				offset += len(id) + 1
+				continue
+			}
+			var ierr *InvalidValueError
+			if errors.As(err, &ierr) {
+				ierr.Index = idx
+				ierr.Offset = offset
+			}
+			return err
+		}
+		bitmask |= enumval
+		warnings = append(warnings, warning)
+		offset += len(id) + 1
+	}
+	names.Warn(warnings...)
+	if !b.merge {
+		// Replace any existing default enum value set on first Set().
+		*b.v = bitmask
+		b.merge = true // ...and next time: merge.
+		return nil
+	}
+	*b.v |= bitmask
+	return nil
+}
+
+// bits returns the bits specified in hexadecimal notation, such as “0x10”, and
+// true, if all these bits are covered by mapped enum values. Otherwise, it
+// returns false.
+func (b *enumBitmask[E]) bits(id string) (E, bool) {
+	hex, ok := strings.CutPrefix(strings.ToLower(id), "0x")
+	if !ok {
+		return 0, false
+	}
+	u, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil || u == 0 {
+		return 0, false
+	}
+	bits := E(u)
+	if uint64(bits) != u || bits&^b.mapped != 0 {
+		return 0, false
+	}
+	return bits, true
+}
+
+// String returns the textual representation of the bitmask enum value,
+// decomposed into the canonical names of its enum values, separated by “|”.
+// Enum values with more bits set, such as composite enum values, take
+// precedence over enum values with fewer bits set; otherwise, enum values are
+// in their declared order (see [WithOrder]) or numerically ordered. Any remaining unknown bits
+// are rendered in hexadecimal notation, such as “read|0x10”. A zero bitmask
+// enum value is rendered as the empty string, unless zero is mapped.
+func (b *enumBitmask[E]) String(names enumMapper[E]) string {
+	bitmask := *b.v
+	if ids := names.Lookup(bitmask); len(ids) > 0 {
+		return ids[0]
+	}
+	if bitmask == 0 {
+		return ""
+	}
+	order, ordered := names.Order()
+	candidates := slices.DeleteFunc(slices.Clone(order), func(enumval E) bool {
+		return enumval == 0 || bitmask&enumval != enumval
+	})
+	if !ordered {
+		slices.Sort(candidates)
+	}
+	slices.SortStableFunc(candidates, func(a, b E) int {
+		return bits.OnesCount64(uint64(b)) - bits.OnesCount64(uint64(a))
+	})
+	n := []string{}
+	remaining := bitmask
+	for _, enumval := range candidates {
+		if remaining&enumval == 0 {
+			continue
+		}
+		n = append(n, names.Lookup(enumval)[0])
+		remaining &^= enumval
+	}
+	if remaining != 0 {
+		n = append(n, fmt.Sprintf("%#x", remaining))
+	}
+	return strings.Join(n, "|")
+}
+
+// NewCompletor returns a cobra Completor that completes bitmask enum values,
+// in the same way as slice enum values get completed.
+func (b *enumBitmask[E]) NewCompletor(names enumMapper[E], help Help[E]) Completor {
+	return newListCompletor(names, help, anySeparator(bitmaskSeparators), nil)
+}
+
+// splitAny splits the specified string at each of the separators, keeping
+// empty elements.
+func splitAny(s string, seps string) []string {
+	elements := []string{}
+	for {
+		idx := strings.IndexAny(s, seps)
+		if idx < 0 {
+			return append(elements, s)
+		}
+		elements = append(elements, s[:idx])
+		s = s[idx+1:]
+	}
+}
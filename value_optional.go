@@ -0,0 +1,65 @@
+// Copyright 2026 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package enumflag
+
+// enumOptional represents an optional scalar enumeration value that is nil
+// until set.
+type enumOptional[E comparable] struct {
+	v **E
+}
+
+// Get returns the optional enum value as a *E, which is nil if not set.
+func (o *enumOptional[E]) Get() any { return *o.v }
+
+// Reset sets the optional enum value back to nil.
+func (o *enumOptional[E]) Reset() { *o.v = nil }
+
+// Set sets the optional enum value to a new enum value corresponding to the
+// passed textual representation, using the additionally specified
+// text-to-value mapping. If the specified textual representation doesn't match
+// any of the defined ones, an error is returned instead and the value isn't
+// changed. In case of deprecated identifiers or enum values a warning gets
+// emitted.
+func (o *enumOptional[E]) Set(val string, names enumMapper[E]) error {
+	enumval, warning, err := names.Parse(val)
+	if err != nil {
+		return err
+	}
+	*o.v = &enumval
+	names.Warn(warning)
+	return nil
+}
+
+// String returns the textual representation of the optional enum value, using
+// the specified text-to-value mapping. It returns an empty string if the
+// optional enum value isn't set, so that [spf13/cobra] doesn't show any
+// default, and "<unknown>" for unmapped enum values.
+//
+// [spf13/cobra]: https://github.com/spf13/cobra
+func (o *enumOptional[E]) String(names enumMapper[E]) string {
+	if *o.v == nil {
+		return ""
+	}
+	if ids := names.Lookup(**o.v); len(ids) > 0 {
+		return ids[0]
+	}
+	return unknown
+}
+
+// NewCompletor returns a cobra Completor that completes enum flag values, the
+// same as for scalar enum flags.
+func (o *enumOptional[E]) NewCompletor(names enumMapper[E], help Help[E]) Completor {
+	return newScalarCompletor(names, help)
+}
@@ -28,21 +28,27 @@ const unknown = "<unknown>"
 type enumScalar[E comparable] struct {
 	v         *E
 	nodefault bool // opts in to accepting a zero enum value as the "none"
+	def       E    // default enum value for resetting.
 }
 
 // Get returns the scalar enum value.
 func (s *enumScalar[E]) Get() any { return *s.v }
 
+// Reset restores the default enum value.
+func (s *enumScalar[E]) Reset() { *s.v = s.def }
+
 // Set the value to the new scalar enum value corresponding to the passed
 // textual representation, using the additionally specified text-to-value
 // mapping. If the specified textual representation doesn't match any of the
-// defined ones, an error is returned instead and the value isn't changed.
+// defined ones, an error is returned instead and the value isn't changed. In
+// case of deprecated identifiers or enum values a warning gets emitted.
 func (s *enumScalar[E]) Set(val string, names enumMapper[E]) error {
-	enumcode, err := names.ValueOf(val)
+	enumcode, warning, err := names.Parse(val)
 	if err != nil {
 		return err
 	}
 	*s.v = enumcode
+	names.Warn(warning)
 	return nil
 }
 
@@ -69,21 +75,33 @@ func (s *enumScalar[E]) String(names enumMapper[E]) string {
 // NewCompletor returns a cobra Completor that completes enum flag values.
 // Please note that shell completion hasn't the notion of case sensitivity or
 // insensitivity, so we cannot take this into account but instead return all
-// available enum value names in their original form.
-func (s *enumScalar[E]) NewCompletor(enums EnumIdentifiers[E], help Help[E]) Completor {
+// available enum value names in their original form. Hidden and deprecated
+// enum identifiers and values aren't completed. Completions are in mapping
+// order, and in case of an explicitly declared order the shell is asked to
+// keep this order.
+func (s *enumScalar[E]) NewCompletor(names enumMapper[E], help Help[E]) Completor {
+	return newScalarCompletor(names, help)
+}
+
+// newScalarCompletor returns a cobra Completor that completes single enum
+// values, as described for [enumScalar.NewCompletor].
+func newScalarCompletor[E comparable](names enumMapper[E], help Help[E]) Completor {
 	completions := []string{}
-	for enumval, enumnames := range enums {
-		helptext := ""
-		if text, ok := help[enumval]; ok {
-			helptext = "\t" + text
-		}
+	order, ordered := names.Order()
+	for _, enumval := range order {
+		enumnames := names.Listed()[enumval]
+		helptext := names.helptext(help, enumval)
 		// complete not only the canonical enum value name, but also all other
 		// (alias) names.
 		for _, name := range enumnames {
 			completions = append(completions, name+helptext)
 		}
 	}
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if ordered {
+		directive |= cobra.ShellCompDirectiveKeepOrder
+	}
 	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
-		return completions, cobra.ShellCompDirectiveNoFileComp
+		return completions, directive
 	}
 }
@@ -15,8 +15,9 @@
 package enumflag
 
 import (
+	"errors"
+	"fmt"
 	"slices"
-	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -25,90 +26,466 @@ import (
 // set, and stringified.
 type enumSlice[E comparable] struct {
 	v     *[]E
-	merge bool // replace the complete slice or merge values?
+	def   []E           // default enum values for resetting.
+	list  listSeparator // separator and quoting of elements.
+	merge bool          // replace the complete slice or merge values?
+	edit  bool          // accept "+foo" and "-foo" edit operators?
+	all   metaKeyword   // optional keyword for all (non-hidden) enum values.
+	none  metaKeyword   // optional keyword for no enum values.
+
+	ignoreEmpty bool // skip empty elements instead of rejecting them?
+	parseable   bool // render String in a format Set accepts?
+
+	constraints constraints[E] // optional count and exclusivity constraints.
+	policy      slicePolicy[E] // optional duplicate and order policies.
+}
+
+// operator returns the edit operator of the specified element, if edit
+// operators are enabled, as well as the element without its edit operator.
+// Otherwise, it returns a zero operator and the element unchanged.
+func (s *enumSlice[E]) operator(element string) (byte, string) {
+	if !s.edit || element == "" || (element[0] != '+' && element[0] != '-') {
+		return 0, element
+	}
+	return element[0], element[1:]
 }
 
-// Get returns the slice enum values.
-func (s *enumSlice[E]) Get() any { return *s.v }
+// Get returns the slice enum values, adjusted to the duplicate and order
+// policies.
+func (s *enumSlice[E]) Get() any { return s.values() }
+
+// Reset restores the default enum values and forgets about any previous Set.
+func (s *enumSlice[E]) Reset() {
+	*s.v = slices.Clone(s.def)
+	s.merge = false
+}
+
+// merging returns true if the next Set should merge with the current enum
+// values, taking the accumulation policy into account.
+func (s *enumSlice[E]) merging() bool {
+	return s.merge && s.policy.accumulation == MergeRepeats
+}
+
+// values returns the slice enum values, adjusted to the duplicate and order
+// policies, without modifying the slice enum variable.
+func (s *enumSlice[E]) values() []E {
+	if !s.policy.active() {
+		return *s.v
+	}
+	return s.policy.normalize(slices.Clone(*s.v))
+}
 
 // Set or merge one or more values of the new scalar enum value corresponding to
-// the passed textual representation, using the additionally specified
+// the passed textual representation, with elements separated by the configured
+// separator and optionally quoted, using the additionally specified
 // text-to-value mapping. If the specified textual representation doesn't match
 // any of the defined ones, an error is returned instead and the value isn't
-// changed. The first call to Set will always clear any previous default value.
+// changed. In case of deprecated identifiers or enum values warnings get
+// emitted. The first call to Set will always clear any previous default value.
 // All subsequent calls to Set will merge the specified enum values with the
 // current enum values.
+//
+// With edit operators enabled, enum values prefixed with “+” get added to the
+// current enum values, including any default values, while enum values
+// prefixed with “-” get removed. Enum values without any prefix are added,
+// too, if any other enum value is prefixed. An empty textual representation
+// clears the slice.
+//
+// The optional “all” meta keyword expands to all non-hidden enum values, and
+// the optional “none” meta keyword clears the slice, including any enum values
+// preceding it.
+//
+// Depending on the accumulation policy, subsequent calls to Set either merge,
+// replace, or are rejected.
+//
+// With a parseable textual representation (see [WithParseableString]), an
+// empty textual representation clears the slice, too.
+//
+// If the resulting enum values would violate any constraints, a
+// [*ConstraintError] is returned instead and the value isn't changed.
 func (s *enumSlice[E]) Set(val string, names enumMapper[E]) error {
+	if s.merge && s.policy.accumulation == RejectRepeats {
+		return &ConstraintError{
+			Kind:   RepeatConstraint,
+			reason: "must be specified only once",
+		}
+	}
+	if err := s.constraints.checkInput(val); err != nil {
+		return err
+	}
+	if (s.edit || s.parseable) && val == "" {
+		if err := s.constraints.check([]E{}, names); err != nil {
+			return err
+		}
+		*s.v = []E{}
+		s.merge = true
+		return nil
+	}
 	// First parse and convert the textual enum values into their
 	// program-internal codes.
-	ids := strings.Split(val, ",")
-	enumvals := make([]E, 0, len(ids)) // ...educated guess
-	for _, id := range ids {
-		enumval, err := names.ValueOf(id)
+	elements, err := s.list.split(val)
+	if err != nil {
+		return err
+	}
+	enumvals := make([]E, 0, len(elements)) // ...educated guess
+	ops := make([]byte, 0, len(elements))
+	edits := false
+	clear := false
+	warnings := []string{}
+	for idx, element := range elements {
+		if element.text == "" && s.ignoreEmpty {
+			continue
+		}
+		op, id := s.operator(element.text)
+		offset := element.offset
+		if op != 0 {
+			edits = true
+			offset++
+		}
+		switch {
+		case s.none.matches(id, names.MatchesKeyword):
+			clear = true
+			enumvals, ops, warnings = enumvals[:0], ops[:0], warnings[:0]
+			continue
+		case s.all.matches(id, names.MatchesKeyword):
+			for _, enumval := range names.Visible() {
+				enumvals = append(enumvals, enumval)
+				ops = append(ops, op)
+			}
+			continue
+		}
+		enumval, warning, err := names.Parse(id)
 		if err != nil {
+			var ierr *InvalidValueError
+			if errors.As(err, &ierr) {
+				ierr.Index = idx
+				ierr.Offset = offset
+			}
 			return err
 		}
 		enumvals = append(enumvals, enumval)
+		ops = append(ops, op)
+		warnings = append(warnings, warning)
+	}
+	var result []E
+	switch {
+	case edits:
+		// Edit the current enum values, including any default values.
+		result = slices.Clone(*s.v)
+		if clear {
+			result = []E{}
+		}
+		for idx, enumval := range enumvals {
+			if ops[idx] == '-' {
+				result = slices.DeleteFunc(result, func(e E) bool { return e == enumval })
+				continue
+			}
+			if !slices.Contains(result, enumval) {
+				result = append(result, enumval)
+			}
+		}
+	case !s.merging() || clear:
+		// Replace any existing default enum value set on first Set(), or any
+		// earlier enum values when the last occurrence wins.
+		result = enumvals
+	default:
+		// Later, merge with the existing enum values.
+		result = slices.Clone(*s.v)
+		for _, enumval := range enumvals {
+			if s.policy.mergesPresent() && slices.Contains(result, enumval) {
+				continue
+			}
+			result = append(result, enumval)
+		}
+	}
+	if err := s.update(result, names); err != nil {
+		return err
 	}
-	if !s.merge {
-		// Replace any existing default enum value set on first Set().
-		*s.v = enumvals
-		s.merge = true // ...and next time: merge.
+	names.Warn(warnings...)
+	s.merge = true // ...and next time: merge.
+	return nil
+}
+
+// update sets the slice enum value to the specified enum values, adjusted to
+// the duplicate and order policies, unless they violate any constraints. In
+// the latter case, a [*ConstraintError] is returned instead and the value
+// isn't changed.
+func (s *enumSlice[E]) update(enumvals []E, names enumMapper[E]) error {
+	if s.policy.duplicates == RejectDuplicates {
+		if dupe, ok := duplicate(enumvals); ok {
+			name := canonical(dupe, names)
+			return &ConstraintError{
+				Kind:      DuplicateConstraint,
+				Duplicate: name,
+				reason:    fmt.Sprintf("'%s' must not be specified more than once", name),
+			}
+		}
+	}
+	enumvals = s.policy.normalize(enumvals)
+	if err := s.constraints.check(enumvals, names); err != nil {
+		return err
+	}
+	*s.v = enumvals
+	return nil
+}
+
+// Append adds the enum value corresponding to the specified single
+// identifier, without any splitting, quoting, edit operators, or meta
+// keywords. Append doesn't count as a Set, so it neither replaces any
+// default enum values nor is subject to the accumulation policy. If the
+// identifier doesn't match, or the resulting enum values would violate any
+// constraints, an error is returned instead and the value isn't changed.
+func (s *enumSlice[E]) Append(val string, names enumMapper[E]) error {
+	enumval, warning, err := names.Parse(val)
+	if err != nil {
+		return err
+	}
+	result := slices.Clone(*s.v)
+	if s.policy.mergesPresent() && slices.Contains(result, enumval) {
+		names.Warn(warning)
 		return nil
 	}
-	// Later, merge with the existing enum values.
-	for _, enumval := range enumvals {
-		if slices.Index(*s.v, enumval) >= 0 {
-			continue
+	if err := s.update(append(result, enumval), names); err != nil {
+		return err
+	}
+	names.Warn(warning)
+	return nil
+}
+
+// Replace replaces the enum values with the enum values corresponding to the
+// specified identifiers, one per element and without any splitting, quoting,
+// edit operators, or meta keywords. If any identifier doesn't match, or the
+// resulting enum values would violate any constraints, an error is returned
+// instead and the value isn't changed.
+func (s *enumSlice[E]) Replace(vals []string, names enumMapper[E]) error {
+	enumvals := make([]E, 0, len(vals))
+	warnings := make([]string, 0, len(vals))
+	for idx, val := range vals {
+		enumval, warning, err := names.Parse(val)
+		if err != nil {
+			var ierr *InvalidValueError
+			if errors.As(err, &ierr) {
+				ierr.Index = idx
+			}
+			return err
 		}
-		*s.v = append(*s.v, enumval)
+		enumvals = append(enumvals, enumval)
+		warnings = append(warnings, warning)
+	}
+	if err := s.update(enumvals, names); err != nil {
+		return err
 	}
+	names.Warn(warnings...)
 	return nil
 }
 
-// String returns the textual representation of the slice enum value, using the
-// specified text-to-value mapping.
-func (s *enumSlice[E]) String(names enumMapper[E]) string {
-	n := make([]string, 0, len(*s.v))
-	for _, enumval := range *s.v {
+// GetSlice returns the canonical names of the slice enum values, adjusted to
+// the duplicate and order policies.
+func (s *enumSlice[E]) GetSlice(names enumMapper[E]) []string {
+	enumvals := s.values()
+	n := make([]string, 0, len(enumvals))
+	for _, enumval := range enumvals {
 		if enumnames := names.Lookup(enumval); len(enumnames) > 0 {
 			n = append(n, enumnames[0])
 			continue
 		}
 		n = append(n, unknown)
 	}
-	return "[" + strings.Join(n, ",") + "]"
+	return n
+}
+
+// String returns the textual representation of the slice enum value, using the
+// specified text-to-value mapping. Unless parseable, the textual
+// representation is enclosed in brackets and unmapped enum values are shown
+// as "<unknown>". Otherwise, unmapped enum values are left out.
+func (s *enumSlice[E]) String(names enumMapper[E]) string {
+	if s.parseable {
+		text, _ := s.Text(names, false)
+		return text
+	}
+	return "[" + s.list.join(s.GetSlice(names)) + "]"
+}
+
+// quote returns the specified identifier quoted as necessary for Set to
+// accept it as a single element.
+func (s *enumSlice[E]) quote(id string) string { return s.list.quote(id) }
+
+// Text returns the textual representation of the slice enum value in a
+// format that Set accepts, using the configured separator and quoting. If
+// strict, unmapped enum values cause an error; otherwise, they are left out.
+func (s *enumSlice[E]) Text(names enumMapper[E], strict bool) (string, error) {
+	enumvals := s.values()
+	n := make([]string, 0, len(enumvals))
+	for _, enumval := range enumvals {
+		enumnames := names.Lookup(enumval)
+		if len(enumnames) == 0 {
+			if strict {
+				return "", fmt.Errorf("enum value %v isn't mapped", enumval)
+			}
+			continue
+		}
+		n = append(n, enumnames[0])
+	}
+	return s.list.join(n), nil
 }
 
 // NewCompletor returns a cobra Completor that completes enum flag values.
-func (s *enumSlice[E]) NewCompletor(enums EnumIdentifiers[E], help Help[E]) Completor {
-	completions := []string{}
-	for enumval, enumnames := range enums {
-		helptext := ""
-		if text, ok := help[enumval]; ok {
-			helptext = "\t" + text
+// Identifiers already present in the slice being completed aren't offered
+// again, taking the case sensitivity into account. Hidden and deprecated enum
+// identifiers and values aren't completed. Completions are in mapping order,
+// and in case of an explicitly declared order the shell is asked to keep this
+// order.
+//
+// With edit operators enabled, completing an enum value prefixed with “+”
+// offers only enum values not yet present in the current enum values, while
+// “-” offers only enum values already present.
+func (s *enumSlice[E]) NewCompletor(names enumMapper[E], help Help[E]) Completor {
+	var completable func([]string, E) bool
+	if s.constraints.limitCompletion() {
+		completable = func(completes []string, candidate E) bool {
+			return s.constraints.completable(s.present(completes, false, names), candidate)
 		}
+	}
+	complete := newListCompletor(names, help, s.list, completable, s.all, s.none)
+	if !s.edit {
+		return complete
+	}
+	order, ordered := names.Order()
+	directive := cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	if ordered {
+		directive |= cobra.ShellCompDirectiveKeepOrder
+	}
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix, completes := s.list.tail(toComplete)
+		op, _ := s.operator(toComplete[len(prefix):])
+		if op == 0 {
+			return complete(cmd, args, toComplete)
+		}
+		prefix += string(op)
+		present := s.present(completes, true, names)
+		for idx, complete := range completes {
+			_, completes[idx] = s.operator(complete)
+		}
+		completions := []string{}
+		for _, enumval := range order {
+			if slices.Contains(present, enumval) == (op == '+') {
+				continue
+			}
+			if op == '+' && !s.constraints.completable(present, enumval) {
+				continue
+			}
+			helptext := names.helptext(help, enumval)
+			for _, id := range names.Listed()[enumval] {
+				if slices.ContainsFunc(completes, func(complete string) bool {
+					return names.Matches(complete, id)
+				}) {
+					continue
+				}
+				completions = append(completions, prefix+s.list.quote(id)+helptext)
+			}
+		}
+		return completions, directive
+	}
+}
+
+// present returns the enum values that would result from setting the
+// specified (complete) elements, without actually changing the slice enum
+// value and without checking any constraints. If editing, unprefixed elements
+// are considered to be additions. Invalid elements are ignored.
+func (s *enumSlice[E]) present(elements []string, editing bool, names enumMapper[E]) []E {
+	v := slices.Clone(*s.v)
+	if len(elements) == 0 {
+		if !s.merging() && !editing {
+			return []E{}
+		}
+		return v
+	}
+	dry := *s
+	dry.v = &v
+	dry.constraints = constraints[E]{}
+	dry.merge = s.merging()
+	dry.policy.accumulation = MergeRepeats
+	names.warn = func(string) {}
+	valid := make([]string, 0, len(elements))
+	for _, element := range elements {
+		op, id := s.operator(element)
+		if !dry.all.matches(id, names.MatchesKeyword) && !dry.none.matches(id, names.MatchesKeyword) {
+			if _, err := names.ValueOf(id); err != nil {
+				continue
+			}
+		}
+		if editing && op == 0 {
+			element = "+" + element
+		}
+		valid = append(valid, element)
+	}
+	if len(valid) == 0 || dry.Set(s.list.join(valid), names) != nil {
+		return slices.Clone(*s.v)
+	}
+	return v
+}
+
+// newListCompletor returns a cobra Completor that completes lists of enum
+// values, split by the specified listSplitter, as well as any optional meta
+// keywords. Identifiers and keywords already present in the list
+// being completed aren't offered again. Additionally, enum values are only
+// offered if the optional completable function returns true, given the
+// (complete) elements of the list being completed.
+func newListCompletor[E comparable](
+	names enumMapper[E],
+	help Help[E],
+	list listSplitter,
+	completable func(completes []string, candidate E) bool,
+	keywords ...metaKeyword,
+) Completor {
+	type completion struct {
+		id      string
+		help    string
+		enumval E
+		keyword bool
+	}
+	completions := []completion{}
+	order, ordered := names.Order()
+	for _, enumval := range order {
+		enumnames := names.Listed()[enumval]
+		helptext := names.helptext(help, enumval)
 		// complete not only the canonical enum value name, but also all other
 		// (alias) names.
 		for _, name := range enumnames {
-			completions = append(completions, name+helptext)
+			completions = append(completions, completion{
+				id:      name,
+				help:    helptext,
+				enumval: enumval,
+			})
 		}
 	}
-	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		prefix := ""
-		completes := []string{}
-		if lastComma := strings.LastIndex(toComplete, ","); lastComma >= 0 {
-			prefix = toComplete[:lastComma+1] // ...Prof J. won't ever like this variable name
-			completes = strings.Split(prefix, ",")
-			completes = completes[:len(completes)-1] // remove last empty element
+	for _, keyword := range keywords {
+		if keyword.keyword != "" {
+			completions = append(completions, completion{
+				id:      keyword.keyword,
+				help:    keyword.helptext(),
+				keyword: true,
+			})
 		}
+	}
+	directive := cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	if ordered {
+		directive |= cobra.ShellCompDirectiveKeepOrder
+	}
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix, completes := list.tail(toComplete)
 		filteredCompletions := make([]string, 0, len(completions))
 		for _, completion := range completions {
-			if slices.Contains(completes, strings.Split(completion, "\t")[0]) {
+			if slices.ContainsFunc(completes, func(complete string) bool {
+				return names.Matches(complete, completion.id) || names.MatchesKeyword(complete, completion.id)
+			}) {
+				continue
+			}
+			if !completion.keyword && completable != nil && !completable(completes, completion.enumval) {
 				continue
 			}
-			filteredCompletions = append(filteredCompletions, prefix+completion)
+			filteredCompletions = append(filteredCompletions, prefix+list.quote(completion.id)+completion.help)
 		}
-		return filteredCompletions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		return filteredCompletions, directive
 	}
 }
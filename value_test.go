@@ -56,7 +56,8 @@ var _ = Describe("enum values", func() {
 
 		DescribeTable("completion",
 			func(toc string, expected []string) {
-				c := (&enumScalar[FooModeTest]{}).NewCompletor(FooModeIdentifiersTest, nil)
+				c := (&enumScalar[FooModeTest]{}).NewCompletor(
+					newEnumMapper(FooModeIdentifiersTest, EnumCaseSensitive), nil)
 				actual, _ := c(nil, nil, toc)
 				Expect(actual).To(ConsistOf(expected))
 			},
@@ -65,7 +66,8 @@ var _ = Describe("enum values", func() {
 		)
 
 		It("completes with help", func() {
-			c := (&enumScalar[FooModeTest]{}).NewCompletor(FooModeIdentifiersTest, FooModeHelp)
+			c := (&enumScalar[FooModeTest]{}).NewCompletor(
+				newEnumMapper(FooModeIdentifiersTest, EnumCaseSensitive), FooModeHelp)
 			actual, _ := c(nil, nil, "")
 			Expect(actual).To(ConsistOf([]string{
 				"foo\tfoo it",
@@ -118,7 +120,8 @@ var _ = Describe("enum values", func() {
 
 		DescribeTable("completion",
 			func(toc string, expected []string) {
-				c := (&enumSlice[FooModeTest]{}).NewCompletor(FooModeIdentifiersTest, nil)
+				c := (&enumSlice[FooModeTest]{}).NewCompletor(
+					newEnumMapper(FooModeIdentifiersTest, EnumCaseSensitive), nil)
 				actual, _ := c(nil, nil, toc)
 				Expect(actual).To(ConsistOf(expected))
 			},
@@ -134,6 +137,13 @@ var _ = Describe("enum values", func() {
 				[]string{"foo,koo,bar", "foo,koo,Bar", "foo,koo,baz"}),
 		)
 
+		It("filters present values taking case insensitivity into account", func() {
+			c := (&enumSlice[FooModeTest]{}).NewCompletor(
+				newEnumMapper(FooModeIdentifiersTest, EnumCaseInsensitive), nil)
+			actual, _ := c(nil, nil, "BAR,")
+			Expect(actual).To(ConsistOf("BAR,foo", "BAR,baz"))
+		})
+
 	})
 
 })